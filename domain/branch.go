@@ -0,0 +1,39 @@
+package domain
+
+import "errors"
+
+// ErrBranchNotFound is returned when a repository has no branch with that name
+var ErrBranchNotFound = errors.New("branch not found")
+
+// Branch is a git branch and the SHA of the commit at its head
+type Branch struct {
+	Name *string `json:"name,omitempty"`
+	SHA  *string `json:"sha,omitempty"`
+}
+
+// BranchProtection is the protection policy applied to a branch
+type BranchProtection struct {
+	RequiredStatusChecks *RequiredStatusChecks `json:"required_status_checks"`
+	RequiredReviews      *RequiredReviews      `json:"required_reviews"`
+	EnforceAdmins        bool                  `json:"enforce_admins"`
+	Restrictions         *PushRestrictions     `json:"restrictions"`
+}
+
+// RequiredStatusChecks lists the status checks that must pass before merging into a branch
+type RequiredStatusChecks struct {
+	Strict   bool     `json:"strict"`
+	Contexts []string `json:"contexts"`
+}
+
+// RequiredReviews defines the reviews a pull request needs before it can be merged
+type RequiredReviews struct {
+	DismissStaleReviews          bool `json:"dismiss_stale_reviews"`
+	RequireCodeOwnerReviews      bool `json:"require_code_owner_reviews"`
+	RequiredApprovingReviewCount int  `json:"required_approving_review_count"`
+}
+
+// PushRestrictions limits the users and teams that can push to a branch
+type PushRestrictions struct {
+	Users []string `json:"users"`
+	Teams []string `json:"teams"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Permission levels of a repository collaborator
+const (
+	PermissionPull  = "pull"
+	PermissionPush  = "push"
+	PermissionAdmin = "admin"
+)
+
+// ErrInvalidPermission is returned when a collaborator is added with an unknown permission
+var ErrInvalidPermission = errors.New("permission must be pull, push or admin")
+
+// Collaborator is a user with access to a repository
+type Collaborator struct {
+	ID         *int    `json:"id,omitempty"`
+	Login      *string `json:"login,omitempty"`
+	Permission *string `json:"permission,omitempty"`
+}
+
+// Invitation is a pending invitation to collaborate on a repository
+type Invitation struct {
+	ID         *int       `json:"id,omitempty"`
+	Invitee    *string    `json:"invitee,omitempty"`
+	Inviter    *string    `json:"inviter,omitempty"`
+	Permission *string    `json:"permission,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	HTMLURL    *string    `json:"html_url,omitempty"`
+}
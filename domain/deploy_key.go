@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Key pair types the service can generate
+const (
+	KeyTypeED25519 = "ed25519"
+	KeyTypeRSA     = "rsa"
+)
+
+// How the private half of a generated key is protected
+const (
+	KeyEncryptionNone       = "none"
+	KeyEncryptionPassphrase = "passphrase"
+	KeyEncryptionRecipient  = "recipient"
+)
+
+var (
+	// ErrInvalidKey is returned when a public key is not in the authorized_keys format
+	ErrInvalidKey = errors.New("the key is not an authorized_keys public key")
+	// ErrUnsupportedKey is returned for DSA keys
+	ErrUnsupportedKey = errors.New("DSA keys are not supported")
+	// ErrWeakKey is returned for RSA keys shorter than 2048 bits
+	ErrWeakKey = errors.New("RSA keys must have at least 2048 bits")
+	// ErrKeyAccessConflict is returned when a repository already has a deploy key
+	// with another read_only setting
+	ErrKeyAccessConflict = errors.New("the repository already has this deploy key with another read_only setting")
+	// ErrInvalidKeyType is returned when a key pair of an unknown type is requested
+	ErrInvalidKeyType = errors.New("key type must be ed25519 or rsa")
+	// ErrInvalidRecipient is returned when the recipient is not a base64 encoded curve25519 public key
+	ErrInvalidRecipient = errors.New("recipient must be a base64 encoded curve25519 public key")
+	// ErrKeyEncryption is returned when a key pair is requested with both a passphrase and a recipient
+	ErrKeyEncryption = errors.New("the private key can be encrypted with a passphrase or a recipient, not both")
+)
+
+// KeyRequest asks for a key pair generated by the service
+type KeyRequest struct {
+	Title    *string `json:"title,omitempty"`
+	ReadOnly *bool   `json:"read_only,omitempty"`
+	// Type is ed25519 when empty, RSA keys are 4096 bits
+	Type string `json:"type,omitempty"`
+	// Passphrase encrypts the private key in the OpenSSH format
+	Passphrase string `json:"passphrase,omitempty"`
+	// Recipient is a curve25519 public key, the private key is returned in a
+	// libsodium sealed box that only its owner can open
+	Recipient string `json:"recipient,omitempty"`
+}
+
+// GeneratedKey is a deploy key generated by the service along with its private
+// half, which is not stored and only returned once
+type GeneratedKey struct {
+	Key        Key    `json:"deploy_key"`
+	PrivateKey string `json:"private_key"`
+	Encryption string `json:"encryption"`
+}
+
+// States of a deploy key rotation
+const (
+	// RotationPending is saved before the replacement key is created
+	RotationPending = "pending"
+	// RotationCreated has both keys registered until the rotation is confirmed
+	// or its grace period ends
+	RotationCreated = "created"
+	// RotationCompleted has the old key deleted
+	RotationCompleted = "completed"
+)
+
+var (
+	// ErrKeyNotFound is returned when a repository has no deploy key with the title to rotate
+	ErrKeyNotFound = errors.New("no deploy key with that title")
+	// ErrAmbiguousKey is returned when more than one deploy key has the title to rotate
+	ErrAmbiguousKey = errors.New("more than one deploy key with that title")
+	// ErrRotationInProgress is returned when a key already has a rotation that is not completed
+	ErrRotationInProgress = errors.New("the key has a rotation in progress")
+	// ErrRotationNotFound is returned when a rotation is not in the store
+	ErrRotationNotFound = errors.New("rotation not found")
+	// ErrRotationState is returned when a rotation cannot be confirmed in its state
+	ErrRotationState = errors.New("the rotation has no replacement key to confirm")
+	// ErrNoRotationStore is returned when rotations are requested and they are not stored
+	ErrNoRotationStore = errors.New("key rotations are not stored")
+)
+
+// RotationRequest asks to replace the deploy key with a title, the replacement
+// is generated by the service unless PublicKey is set
+type RotationRequest struct {
+	KeyRequest
+	PublicKey *string `json:"public_key,omitempty"`
+}
+
+// KeyRotation tracks the replacement of a deploy key, both key IDs are saved
+// so an interrupted rotation can be resumed
+type KeyRotation struct {
+	ID          string     `json:"id"`
+	Owner       string     `json:"owner"`
+	Repo        string     `json:"repo"`
+	Title       string     `json:"title"`
+	State       string     `json:"state"`
+	OldKeyID    int        `json:"old_key_id"`
+	NewKeyID    *int       `json:"new_key_id,omitempty"`
+	PublicKey   *string    `json:"public_key,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	DeleteAfter *time.Time `json:"delete_after,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
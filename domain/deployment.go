@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Deployment is a github deployment of a ref to an environment. RequiredContexts
+// are the status checks that must pass on the ref before it is deployed, none
+// are required when it is nil.
+type Deployment struct {
+	ID               *int       `json:"id,omitempty"`
+	Ref              *string    `json:"ref,omitempty"`
+	SHA              *string    `json:"sha,omitempty"`
+	Task             *string    `json:"task,omitempty"`
+	Environment      *string    `json:"environment,omitempty"`
+	Description      *string    `json:"description,omitempty"`
+	RequiredContexts *[]string  `json:"required_contexts,omitempty"`
+	Creator          *string    `json:"creator,omitempty"`
+	CreatedAt        *time.Time `json:"created_at,omitempty"`
+}
+
+// DeploymentStatus is a state transition of a deployment
+type DeploymentStatus struct {
+	ID             *int       `json:"id,omitempty"`
+	State          *string    `json:"state,omitempty"`
+	Description    *string    `json:"description,omitempty"`
+	LogURL         *string    `json:"log_url,omitempty"`
+	EnvironmentURL *string    `json:"environment_url,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+}
+
+// Deployment states accepted by github
+const (
+	DeploymentQueued     = "queued"
+	DeploymentInProgress = "in_progress"
+	DeploymentPending    = "pending"
+	DeploymentSuccess    = "success"
+	DeploymentFailure    = "failure"
+	DeploymentError      = "error"
+	DeploymentInactive   = "inactive"
+)
+
+// ErrInvalidDeploymentState is returned when a deployment status has an unknown state
+var ErrInvalidDeploymentState = errors.New("invalid deployment state")
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// GPGKey is a GPG key of a github account
+type GPGKey struct {
+	ID    *int    `json:"id,omitempty"`
+	KeyID *string `json:"key_id,omitempty"`
+	// PublicKey is ASCII armored when a key is created, github returns it
+	// base64 encoded
+	PublicKey *string    `json:"public_key,omitempty"`
+	Emails    []string   `json:"emails,omitempty"`
+	CanSign   *bool      `json:"can_sign,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
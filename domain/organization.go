@@ -0,0 +1,21 @@
+package domain
+
+// Organization is an organization the user is a member of, Role is admin for
+// the owners of the organization and member for everyone else
+type Organization struct {
+	ID          *int    `json:"id,omitempty"`
+	Login       *string `json:"login,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Role        *string `json:"role,omitempty"`
+}
+
+// Team is a team of an organization, Permission is the permission its
+// repositories are added with by default
+type Team struct {
+	ID          *int    `json:"id,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Slug        *string `json:"slug,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Privacy     *string `json:"privacy,omitempty"`
+	Permission  *string `json:"permission,omitempty"`
+}
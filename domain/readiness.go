@@ -0,0 +1,23 @@
+package domain
+
+// Results of a readiness check, from best to worst
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// Check is the result of a readiness check
+type Check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// ReadinessReport tells if a repository at a ref is ready to be provisioned,
+// Status is the worst status of its checks
+type ReadinessReport struct {
+	Ref    string  `json:"ref"`
+	Status string  `json:"status"`
+	Checks []Check `json:"checks"`
+}
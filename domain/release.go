@@ -0,0 +1,44 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrEmptyTagMessage is returned when an annotated tag has no message
+	ErrEmptyTagMessage = errors.New("an annotated tag needs a message")
+	// ErrInvalidTagger is returned when an annotated tag has no tagger name or
+	// a tagger email that is not an address
+	ErrInvalidTagger = errors.New("an annotated tag needs the name and the email of its tagger")
+)
+
+// Tag is a git tag, it is created as an annotated tag when it has a message
+type Tag struct {
+	Name    string `json:"name"`
+	Target  string `json:"target,omitempty"`
+	SHA     string `json:"sha,omitempty"`
+	Message string `json:"message,omitempty"`
+	Tagger  string `json:"tagger,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+// Release is a github release of a repository
+type Release struct {
+	ID              *int           `json:"id,omitempty"`
+	TagName         *string        `json:"tag_name,omitempty"`
+	TargetCommitish *string        `json:"target_commitish,omitempty"`
+	Name            *string        `json:"name,omitempty"`
+	Body            *string        `json:"body,omitempty"`
+	Draft           *bool          `json:"draft,omitempty"`
+	Prerelease      *bool          `json:"prerelease,omitempty"`
+	HTMLURL         *string        `json:"html_url,omitempty"`
+	Assets          []ReleaseAsset `json:"assets,omitempty"`
+}
+
+// ReleaseAsset is a file uploaded to a release
+type ReleaseAsset struct {
+	ID          *int    `json:"id,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	Label       *string `json:"label,omitempty"`
+	ContentType *string `json:"content_type,omitempty"`
+	Size        *int    `json:"size,omitempty"`
+	DownloadURL *string `json:"download_url,omitempty"`
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Repository is a github repository, Permissions are the ones of the
+// authenticated user
+type Repository struct {
+	ID            *int                   `json:"id,omitempty"`
+	Owner         *string                `json:"owner,omitempty"`
+	Name          *string                `json:"name,omitempty"`
+	FullName      *string                `json:"full_name,omitempty"`
+	Description   *string                `json:"description,omitempty"`
+	Private       *bool                  `json:"private,omitempty"`
+	HTMLURL       *string                `json:"html_url,omitempty"`
+	CloneURL      *string                `json:"clone_url,omitempty"`
+	SSHURL        *string                `json:"ssh_url,omitempty"`
+	DefaultBranch *string                `json:"default_branch,omitempty"`
+	Language      *string                `json:"language,omitempty"`
+	Topics        []string               `json:"topics,omitempty"`
+	Fork          *bool                  `json:"fork,omitempty"`
+	Archived      *bool                  `json:"archived,omitempty"`
+	Size          *int                   `json:"size,omitempty"`
+	CreatedAt     *time.Time             `json:"created_at,omitempty"`
+	PushedAt      *time.Time             `json:"pushed_at,omitempty"`
+	UpdatedAt     *time.Time             `json:"updated_at,omitempty"`
+	Permissions   *RepositoryPermissions `json:"permissions,omitempty"`
+}
+
+// RepositoryPermissions are the permissions of a user on a repository
+type RepositoryPermissions struct {
+	Admin bool `json:"admin"`
+	Push  bool `json:"push"`
+	Pull  bool `json:"pull"`
+}
+
+// MaxPerPage is the largest page github returns
+const MaxPerPage = 100
+
+// MaxTopics is the largest number of topics github allows in a repository
+const MaxTopics = 20
+
+var (
+	// ErrInvalidVisibility is returned when repositories are filtered by an unknown visibility
+	ErrInvalidVisibility = errors.New("visibility must be all, public or private")
+	// ErrInvalidAffiliation is returned when repositories are filtered by an unknown affiliation
+	ErrInvalidAffiliation = errors.New("affiliation must be a comma separated list of owner, collaborator and organization_member")
+	// ErrInvalidRepoType is returned when repositories are filtered by an unknown type
+	ErrInvalidRepoType = errors.New("type must be all, owner, public, private or member")
+	// ErrRepoTypeConflict is returned when type is combined with visibility or affiliation, github does not allow it
+	ErrRepoTypeConflict = errors.New("type cannot be combined with visibility or affiliation")
+	// ErrInvalidSort is returned when repositories are sorted by an unknown field
+	ErrInvalidSort = errors.New("sort must be created, updated, pushed or full_name")
+	// ErrInvalidDirection is returned when the sort direction is not asc or desc
+	ErrInvalidDirection = errors.New("direction must be asc or desc")
+	// ErrInvalidPage is returned when the page or the page size are out of range
+	ErrInvalidPage = errors.New("page must be positive and per_page between 1 and 100")
+	// ErrOwnReposFilter is returned when the repositories of another user are
+	// filtered by something only the authenticated user can filter by
+	ErrOwnReposFilter = errors.New("visibility, affiliation and the public and private types only filter the repositories of the authenticated user")
+)
+
+var (
+	// ErrRepoNotFound is returned when github has no repository with that name
+	// or the user cannot see it
+	ErrRepoNotFound = errors.New("repository not found")
+	// ErrTransferPending is returned when github accepted a transfer that did not
+	// finish while it was polled
+	ErrTransferPending = errors.New("the transfer was accepted and is still in progress")
+	// ErrInvalidFork is returned when github does not say where it creates a fork
+	ErrInvalidFork = errors.New("github did not return the location of the fork")
+	// ErrForkPending is returned when github created a fork that was not ready
+	// while it was polled
+	ErrForkPending = errors.New("the fork was created and is still being prepared")
+)
+
+var (
+	// ErrInvalidTopic is returned when a topic is not lowercase letters, numbers
+	// and hyphens starting with a letter or a number, or is longer than 50 characters
+	ErrInvalidTopic = errors.New("topics must be lowercase letters, numbers and hyphens, start with a letter or a number and have at most 50 characters")
+	// ErrTooManyTopics is returned when a repository is given more than MaxTopics topics
+	ErrTooManyTopics = errors.New("a repository can have at most 20 topics")
+)
+
+// RepositoryListOptions filters, sorts and paginates a list of repositories,
+// empty fields use the defaults of github
+type RepositoryListOptions struct {
+	Visibility  string
+	Affiliation string
+	Type        string
+	Sort        string
+	Direction   string
+	Page        int
+	PerPage     int
+}
+
+// Pages has the numbers of the pages around a page of results, a page is zero
+// when there is no such page
+type Pages struct {
+	NextPage  int
+	PrevPage  int
+	FirstPage int
+	LastPage  int
+}
+
+// RepositoryPage is a page of repositories
+type RepositoryPage struct {
+	Pages
+	Repositories []Repository
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrEmptyQuery is returned when a search has no query
+	ErrEmptyQuery = errors.New("the search query is required")
+	// ErrSearchQualifier is returned when a query has its own user, org or repo
+	// qualifier, searches are scoped by the service
+	ErrSearchQualifier = errors.New("the search query cannot have user, org or repo qualifiers, use owner instead")
+	// ErrSearchScope is returned when a search is limited to an owner that is
+	// not the user or one of its organizations
+	ErrSearchScope = errors.New("owner must be the user or one of its organizations")
+	// ErrInvalidSearchSort is returned when the results are sorted by an unknown field
+	ErrInvalidSearchSort = errors.New("invalid sort for this search")
+	// ErrInvalidOrder is returned when the sort order is not asc or desc
+	ErrInvalidOrder = errors.New("order must be asc or desc")
+)
+
+// SearchOptions is a search in the repositories of the user and its
+// organizations, Owner limits it to one of them
+type SearchOptions struct {
+	Query   string
+	Owner   string
+	Sort    string
+	Order   string
+	Page    int
+	PerPage int
+}
+
+// RepositorySearch is a page of repositories found by a search
+type RepositorySearch struct {
+	Pages
+	Total        int
+	Incomplete   bool
+	Repositories []Repository
+}
+
+// CodeResult is a file found by a code search
+type CodeResult struct {
+	Name       *string `json:"name,omitempty"`
+	Path       *string `json:"path,omitempty"`
+	SHA        *string `json:"sha,omitempty"`
+	HTMLURL    *string `json:"html_url,omitempty"`
+	Repository *string `json:"repository,omitempty"`
+}
+
+// CodeSearch is a page of files found by a search
+type CodeSearch struct {
+	Pages
+	Total      int
+	Incomplete bool
+	Results    []CodeResult
+}
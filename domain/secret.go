@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxSecretSize is the largest secret value github accepts, in bytes
+const MaxSecretSize = 48 * 1024
+
+var (
+	// ErrInvalidSecretName is returned when a secret name is not letters, numbers
+	// and underscores, starts with a number or with the GITHUB_ prefix
+	ErrInvalidSecretName = errors.New("secret names must be letters, numbers and underscores, cannot start with a number or with GITHUB_")
+	// ErrEmptySecret is returned when a secret is set without a value
+	ErrEmptySecret = errors.New("the secret value is required")
+	// ErrSecretTooLarge is returned when a secret value is larger than MaxSecretSize
+	ErrSecretTooLarge = errors.New("secret values can have at most 48KB")
+	// ErrInvalidSecretsKey is returned when the public key github returns to
+	// encrypt secrets is not a curve25519 key
+	ErrInvalidSecretsKey = errors.New("the secrets public key of the repository is invalid")
+)
+
+// Secret is a github Actions secret of a repository or an environment, its
+// value is write only
+type Secret struct {
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// SecretsPublicKey is the key secrets are encrypted with before they are sent
+// to github, Key is base64 encoded
+type SecretsPublicKey struct {
+	KeyID string `json:"key_id"`
+	Key   string `json:"key"`
+}
+
+// EncryptedSecret is a secret value in a base64 encoded libsodium sealed box
+// for the public key with KeyID
+type EncryptedSecret struct {
+	EncryptedValue string `json:"encrypted_value"`
+	KeyID          string `json:"key_id"`
+}
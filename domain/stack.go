@@ -0,0 +1,25 @@
+package domain
+
+// Stack is a technology detected in a repository. Version is a hint read from
+// the files of the repository and Evidence are the paths the stack was
+// detected from.
+type Stack struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version,omitempty"`
+	Evidence []string `json:"evidence"`
+}
+
+// Language is the share of a language in the code of a repository as
+// reported by github
+type Language struct {
+	Name    string  `json:"name"`
+	Bytes   int     `json:"bytes"`
+	Percent float64 `json:"percent"`
+}
+
+// StackReport has the stacks detected in a repository at a ref
+type StackReport struct {
+	Ref       string     `json:"ref"`
+	Stacks    []Stack    `json:"stacks"`
+	Languages []Language `json:"languages"`
+}
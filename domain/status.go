@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// CommitStatus is a state reported for a commit by an external service
+type CommitStatus struct {
+	ID          *int       `json:"id,omitempty"`
+	State       *string    `json:"state,omitempty"`
+	Context     *string    `json:"context,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	TargetURL   *string    `json:"target_url,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+}
+
+// CombinedStatus is the overall state of a ref from the latest status of each context
+type CombinedStatus struct {
+	State      *string        `json:"state,omitempty"`
+	SHA        *string        `json:"sha,omitempty"`
+	TotalCount *int           `json:"total_count,omitempty"`
+	Statuses   []CommitStatus `json:"statuses"`
+}
+
+// Commit status states accepted by github
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusError   = "error"
+)
+
+// ErrInvalidCommitState is returned when a commit status has an unknown state
+var ErrInvalidCommitState = errors.New("invalid commit status state")
@@ -1,5 +1,11 @@
 package domain
 
+import "errors"
+
+// ErrNotKeyOwner is returned when the keys of a user other than the one that
+// owns the token are managed, github only manages the keys of the token owner
+var ErrNotKeyOwner = errors.New("keys can only be managed for the user that owns the token")
+
 // User is a type where the user attributes are stored
 type User struct {
 	ID             int
@@ -10,18 +16,8 @@ type User struct {
 
 // Link defines the structure to the navigation links
 type Link struct {
-	Title string
-	URL   string
-}
-
-type Repository struct {
-	Name        *string `json:"name,omitempty"`
-	FullName    *string `json:"full_name,omitempty"`
-	Description *string `json:"description,omitempty"`
-	Private     *bool   `json:"private,omitempty"`
-	HTMLURL     *string `json:"html_url,omitempty"`
-	CloneURL    *string `json:"clone_url,omitempty"`
-	SSHURL      *string `json:"ssh_url,omitempty"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
 }
 
 type Key struct {
@@ -29,6 +25,10 @@ type Key struct {
 	Key   *string `json:"key,omitempty"`
 	Title *string `json:"title,omitempty"`
 	URL   *string `json:"url,omitempty"`
+	// ReadOnly is only used by deploy keys, they are read only unless it is false
+	ReadOnly *bool `json:"read_only,omitempty"`
+	// Fingerprint is the SHA256 fingerprint of the key as printed by ssh-keygen
+	Fingerprint *string `json:"fingerprint,omitempty"`
 }
 
 type File struct {
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Results of processing a webhook delivery
+const (
+	DeliveryReceived  = "received"
+	DeliveryProcessed = "processed"
+	DeliveryFailed    = "failed"
+	DeliveryInvalid   = "invalid"
+)
+
+// ErrDeliveryNotFound is returned when a delivery is not in the store
+var ErrDeliveryNotFound = errors.New("delivery not found")
+
+// ErrNoDeliveryStore is returned when deliveries are requested and they are not stored
+var ErrNoDeliveryStore = errors.New("webhook deliveries are not stored")
+
+// ErrHookSecretNotFound is returned when a hook has no secret in the store
+var ErrHookSecretNotFound = errors.New("hook secret not found")
+
+// WebhookEvent is an event delivered to the service by a github webhook
+type WebhookEvent struct {
+	DeliveryID string
+	Type       string
+	// Payload holds the go-github event type matching Type, or the raw JSON
+	// for event types it does not know
+	Payload interface{}
+}
+
+// Delivery is a webhook delivery as it was received from github along with the
+// result of its last processing
+type Delivery struct {
+	ID          string              `json:"id"`
+	Event       string              `json:"event"`
+	Headers     map[string][]string `json:"headers,omitempty"`
+	Payload     json.RawMessage     `json:"payload,omitempty"`
+	Result      string              `json:"result"`
+	Error       string              `json:"error,omitempty"`
+	Attempts    int                 `json:"attempts"`
+	ReceivedAt  time.Time           `json:"received_at"`
+	ProcessedAt *time.Time          `json:"processed_at,omitempty"`
+}
+
+// Hook is a webhook registered in a repository or an organization
+type Hook struct {
+	ID          *int     `json:"id,omitempty"`
+	URL         *string  `json:"url,omitempty"`
+	ContentType *string  `json:"content_type,omitempty"`
+	Events      []string `json:"events,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	// Secret is returned when the hook is created, or when creating it finds a
+	// hook with the same url that is reused, github never returns it
+	Secret *string `json:"secret,omitempty"`
+}
@@ -0,0 +1,118 @@
+package infrastructure
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// validDeliveryID keeps delivery IDs, which github sends as GUIDs, from
+// escaping the store directory
+var validDeliveryID = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// ErrInvalidDeliveryID is returned when a delivery ID cannot be used as a file name
+var ErrInvalidDeliveryID = errors.New("invalid delivery id")
+
+// DeliveryStore keeps every webhook delivery as a JSON file in a directory
+type DeliveryStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewDeliveryStore creates the directory of the store if it does not exist
+func NewDeliveryStore(path string) (*DeliveryStore, error) {
+	err := os.MkdirAll(path, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeliveryStore{path: path}, nil
+}
+
+// SaveDelivery writes the delivery replacing the previous version
+func (store *DeliveryStore) SaveDelivery(delivery domain.Delivery) error {
+	if !validDeliveryID.MatchString(delivery.ID) {
+		return ErrInvalidDeliveryID
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return writeJSONFile(store.file(delivery.ID), delivery)
+}
+
+// GetDelivery reads a delivery by its ID
+func (store *DeliveryStore) GetDelivery(id string) (*domain.Delivery, error) {
+	if !validDeliveryID.MatchString(id) {
+		return nil, domain.ErrDeliveryNotFound
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.read(store.file(id))
+}
+
+// ListDeliveries returns the deliveries received between from and to sorted by
+// the time they were received, a zero time leaves that end of the range open
+func (store *DeliveryStore) ListDeliveries(from, to time.Time) ([]domain.Delivery, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	files, err := ioutil.ReadDir(store.path)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []domain.Delivery{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		delivery, err := store.read(filepath.Join(store.path, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if !from.IsZero() && delivery.ReceivedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && delivery.ReceivedAt.After(to) {
+			continue
+		}
+
+		deliveries = append(deliveries, *delivery)
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].ReceivedAt.Before(deliveries[j].ReceivedAt)
+	})
+
+	return deliveries, nil
+}
+
+func (store *DeliveryStore) read(path string) (*domain.Delivery, error) {
+	delivery := &domain.Delivery{}
+	err := readJSONFile(path, delivery)
+	if os.IsNotExist(err) {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (store *DeliveryStore) file(id string) string {
+	return filepath.Join(store.path, id+".json")
+}
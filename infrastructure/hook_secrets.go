@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// HookSecretStore keeps the secret of every webhook registered through the
+// service as a JSON file in a directory, named after the hook ID
+type HookSecretStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+type hookSecret struct {
+	ID     int    `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// NewHookSecretStore creates the directory of the store if it does not exist
+func NewHookSecretStore(path string) (*HookSecretStore, error) {
+	err := os.MkdirAll(path, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HookSecretStore{path: path}, nil
+}
+
+// SaveHookSecret writes the secret of a hook replacing the previous one
+func (store *HookSecretStore) SaveHookSecret(id int, secret string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return writeJSONFile(store.file(id), hookSecret{ID: id, Secret: secret})
+}
+
+// GetHookSecret reads the secret of a hook
+func (store *HookSecretStore) GetHookSecret(id int) (string, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	secret := hookSecret{}
+	err := readJSONFile(store.file(id), &secret)
+	if os.IsNotExist(err) {
+		return "", domain.ErrHookSecretNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return secret.Secret, nil
+}
+
+// DeleteHookSecret removes the secret of a hook, removing a missing secret is
+// not an error
+func (store *HookSecretStore) DeleteHookSecret(id int) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	err := os.Remove(store.file(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (store *HookSecretStore) file(id int) string {
+	return filepath.Join(store.path, fmt.Sprintf("%d.json", id))
+}
@@ -0,0 +1,118 @@
+package infrastructure
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// validRotationID matches the hex IDs of the rotations
+var validRotationID = regexp.MustCompile(`^[a-f0-9]+$`)
+
+// RotationStore keeps every deploy key rotation as a JSON file in a directory
+type RotationStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewRotationStore creates the directory of the store if it does not exist
+func NewRotationStore(path string) (*RotationStore, error) {
+	err := os.MkdirAll(path, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RotationStore{path: path}, nil
+}
+
+// SaveRotation writes the rotation replacing the previous version
+func (store *RotationStore) SaveRotation(rotation domain.KeyRotation) error {
+	if !validRotationID.MatchString(rotation.ID) {
+		return domain.ErrRotationNotFound
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return writeJSONFile(filepath.Join(store.path, rotation.ID+".json"), rotation)
+}
+
+// GetRotation reads a rotation by its ID
+func (store *RotationStore) GetRotation(id string) (*domain.KeyRotation, error) {
+	if !validRotationID.MatchString(id) {
+		return nil, domain.ErrRotationNotFound
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.read(filepath.Join(store.path, id+".json"))
+}
+
+// ListRotations returns the rotations of a repository, the most recent last
+func (store *RotationStore) ListRotations(owner, repo string) ([]domain.KeyRotation, error) {
+	return store.list(func(rotation *domain.KeyRotation) bool {
+		return strings.EqualFold(rotation.Owner, owner) && strings.EqualFold(rotation.Repo, repo)
+	})
+}
+
+// ListExpiredRotations returns the rotations of every repository that have both
+// keys after their grace period ended at now, the most recent last
+func (store *RotationStore) ListExpiredRotations(now time.Time) ([]domain.KeyRotation, error) {
+	return store.list(func(rotation *domain.KeyRotation) bool {
+		return rotation.State == domain.RotationCreated && rotation.DeleteAfter != nil && !now.Before(*rotation.DeleteAfter)
+	})
+}
+
+// list returns the rotations match selects sorted by when they started
+func (store *RotationStore) list(match func(rotation *domain.KeyRotation) bool) ([]domain.KeyRotation, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	files, err := ioutil.ReadDir(store.path)
+	if err != nil {
+		return nil, err
+	}
+
+	rotations := []domain.KeyRotation{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		rotation, err := store.read(filepath.Join(store.path, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if match(rotation) {
+			rotations = append(rotations, *rotation)
+		}
+	}
+
+	sort.SliceStable(rotations, func(i, j int) bool {
+		return rotations[i].StartedAt.Before(rotations[j].StartedAt)
+	})
+
+	return rotations, nil
+}
+
+func (store *RotationStore) read(path string) (*domain.KeyRotation, error) {
+	rotation := &domain.KeyRotation{}
+	err := readJSONFile(path, rotation)
+	if os.IsNotExist(err) {
+		return nil, domain.ErrRotationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
@@ -8,12 +8,19 @@ import (
 
 // Configuration stores the fields to configure the application
 type Configuration struct {
-	Port         string   `yaml:"port"`
-	ClientID     string   `yaml:"clientID"`
-	ClientSecret string   `yaml:"clientSecret"`
-	Salt         string   `yaml:"salt"`
-	Scopes       []string `yaml:"scopes,flow"`
-	APIHost      string   `yaml:"apihost"`
+	Port                string   `yaml:"port"`
+	ClientID            string   `yaml:"clientID"`
+	ClientSecret        string   `yaml:"clientSecret"`
+	Salt                string   `yaml:"salt"`
+	Scopes              []string `yaml:"scopes,flow"`
+	APIHost             string   `yaml:"apihost"`
+	WebhookSecrets      []string `yaml:"webhookSecrets,flow"`
+	HookSecretsPath     string   `yaml:"hookSecretsPath"`
+	DeliveriesPath      string   `yaml:"deliveriesPath"`
+	AdminToken          string   `yaml:"adminToken"`
+	RotationsPath       string   `yaml:"rotationsPath"`
+	RotationGracePeriod string   `yaml:"rotationGracePeriod"`
+	ReadinessChecks     []string `yaml:"readinessChecks,flow"`
 }
 
 // GetConfiguration returns the configuration stored in a file
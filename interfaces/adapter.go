@@ -1,6 +1,8 @@
 package interfaces
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -15,7 +17,24 @@ import (
 // Adapter is the signature of an HTTPHandler for middlewares
 type Adapter func(http.Handler) http.Handler
 type repository interface {
-	SetToken(token string)
+	WithToken(token string) *GithubRepository
+}
+
+type repositoryKey struct{}
+
+// withRepository returns a copy of the request whose context holds a github
+// repository authenticated with token, every request gets its own client so
+// concurrent requests never share a token
+func withRepository(r *http.Request, repo repository, token string) *http.Request {
+	ctx := context.WithValue(r.Context(), repositoryKey{}, repo.WithToken(token))
+	return r.WithContext(ctx)
+}
+
+// requestRepository returns the github repository SetToken or GetToken put in
+// the context of the request
+func requestRepository(r *http.Request) (*GithubRepository, bool) {
+	repo, ok := r.Context().Value(repositoryKey{}).(*GithubRepository)
+	return repo, ok
 }
 
 // Notify is a middleware to measure the time that a request takes
@@ -42,8 +61,7 @@ func SetToken(repo repository) Adapter {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			token := r.Header.Get(domain.TokenHeader)
-			repo.SetToken(token)
-			h.ServeHTTP(w, r)
+			h.ServeHTTP(w, withRepository(r, repo, token))
 		})
 	}
 }
@@ -99,8 +117,23 @@ func GetToken(repo repository, apiURL string, salt string) Adapter {
 				}
 			}
 
-			repo.SetToken(token)
+			h.ServeHTTP(w, withRepository(r, repo, token))
+		})
+	}
+}
 
+// AdminToken is a middleware that only lets through requests with the
+// configured admin token as a bearer token, no request is allowed when the
+// token is not configured
+func AdminToken(token string) Adapter {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("authorization")
+			if token == "" || !strings.HasPrefix(auth, "Bearer ") ||
+				subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+				writeError(w, http.StatusUnauthorized, "Invalid admin token")
+				return
+			}
 			h.ServeHTTP(w, r)
 		})
 	}
@@ -2,13 +2,16 @@ package interfaces
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/Tinker-Ware/gh-service/domain"
 	"github.com/google/go-github/github"
+	"github.com/google/go-querystring/query"
 	"golang.org/x/oauth2"
 
 	ghoauth "golang.org/x/oauth2/github"
@@ -18,6 +21,7 @@ import (
 // within an user account
 type GithubRepository struct {
 	client      *github.Client
+	httpClient  *http.Client
 	oauthConfig *oauth2.Config
 	context     context.Context
 }
@@ -106,88 +110,263 @@ func (repo *GithubRepository) SetToken(token string) {
 
 	client := github.NewClient(tc)
 	repo.client = client
+	repo.httpClient = tc
 
 }
 
-// GetAllRepos returns all the repos from an user account
-func (repo GithubRepository) GetAllRepos(username string) ([]domain.Repository, error) {
+// WithToken returns a copy of the repository with its own client for token,
+// the receiver is left untouched
+func (repo GithubRepository) WithToken(token string) *GithubRepository {
+	rp := repo
+	rp.SetToken(token)
+	return &rp
+}
 
-	opt := &github.RepositoryListOptions{
-		ListOptions: github.ListOptions{PerPage: 30},
+// ListRepos returns a page of the repositories of a user, an empty username
+// lists the repositories of the authenticated user
+func (repo GithubRepository) ListRepos(username string, opt domain.RepositoryListOptions) (*domain.RepositoryPage, error) {
+	u := "user/repos"
+	if username != "" {
+		u = fmt.Sprintf("users/%v/repos", username)
+	}
+
+	ghOpt := &github.RepositoryListOptions{
+		Visibility:  opt.Visibility,
+		Affiliation: opt.Affiliation,
+		Type:        opt.Type,
+		Sort:        opt.Sort,
+		Direction:   opt.Direction,
+		ListOptions: github.ListOptions{
+			Page:    opt.Page,
+			PerPage: opt.PerPage,
+		},
 	}
 
-	var allRepos []domain.Repository
-	for {
-		repos, resp, err := repo.client.Repositories.List(repo.context, "", opt)
-		if err != nil {
-			return nil, err
-		}
-		for _, repo := range repos {
-
-			r := domain.Repository{
-				Name:        repo.Name,
-				FullName:    repo.FullName,
-				Description: repo.Description,
-				Private:     repo.Private,
-				HTMLURL:     repo.HTMLURL,
-				CloneURL:    repo.CloneURL,
-				SSHURL:      repo.SSHURL,
-			}
-			allRepos = append(allRepos, r)
-		}
-		if resp.NextPage == 0 {
-			break
-		}
-		opt.ListOptions.Page = resp.NextPage
+	repos := []*ghRepository{}
+	resp, err := repo.repositoryRequest("GET", u, ghOpt, nil, &repos)
+	if err != nil {
+		return nil, err
 	}
 
-	return allRepos, nil
+	page := &domain.RepositoryPage{
+		Pages:        toDomainPages(resp),
+		Repositories: []domain.Repository{},
+	}
+	for _, rp := range repos {
+		page.Repositories = append(page.Repositories, toDomainRepository(rp))
+	}
+
+	return page, nil
+}
+
+func toDomainPages(resp *github.Response) domain.Pages {
+	return domain.Pages{
+		NextPage:  resp.NextPage,
+		PrevPage:  resp.PrevPage,
+		FirstPage: resp.FirstPage,
+		LastPage:  resp.LastPage,
+	}
 }
 
 // GetRepo gets the information from a single repo
 func (repo GithubRepository) GetRepo(username, reponame string) (*domain.Repository, error) {
+	u := fmt.Sprintf("repos/%v/%v", username, reponame)
 
-	rp, _, err := repo.client.Repositories.Get(repo.context, username, reponame)
+	rp := &ghRepository{}
+	_, err := repo.repositoryRequest("GET", u, nil, nil, rp)
+	if isNotFound(err) {
+		return nil, domain.ErrRepoNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	r := &domain.Repository{
-		Name:        rp.Name,
-		FullName:    rp.FullName,
-		Description: rp.Description,
-		Private:     rp.Private,
-		HTMLURL:     rp.HTMLURL,
-		CloneURL:    rp.CloneURL,
-		SSHURL:      rp.SSHURL,
-	}
+	r := toDomainRepository(rp)
+	return &r, nil
+}
 
-	return r, nil
+// isNotFound reports whether github answered a request with a 404
+func isNotFound(err error) bool {
+	e, ok := err.(*github.ErrorResponse)
+	return ok && e.Response != nil && e.Response.StatusCode == http.StatusNotFound
 }
 
-// CreateRepo creates a repository in the github user account
+// CreateRepo creates a repository in the github user account, or in org when
+// it is not empty
 func (repo GithubRepository) CreateRepo(username, reponame, org string, private bool) (*domain.Repository, error) {
-	rp := &github.Repository{
+	u := "user/repos"
+	if org != "" {
+		u = fmt.Sprintf("orgs/%v/repos", org)
+	}
+
+	body := &github.Repository{
 		Name:    github.String(reponame),
 		Private: github.Bool(private),
 	}
-	rp, _, err := repo.client.Repositories.Create(repo.context, org, rp)
 
+	rp := &ghRepository{}
+	_, err := repo.repositoryRequest("POST", u, nil, body, rp)
+	if err != nil {
+		return nil, err
+	}
+
+	r := toDomainRepository(rp)
+	return &r, nil
+}
+
+// repositoryEdit holds the repository fields that go-github cannot edit yet
+type repositoryEdit struct {
+	Archived      *bool   `json:"archived,omitempty"`
+	DefaultBranch *string `json:"default_branch,omitempty"`
+}
+
+const mediaTypeTopicsPreview = "application/vnd.github.mercy-preview+json"
+
+// ghRepository adds the fields missing in github.Repository
+type ghRepository struct {
+	github.Repository
+	Archived *bool    `json:"archived,omitempty"`
+	Topics   []string `json:"topics,omitempty"`
+}
+
+// repositoryRequest sends a request that returns repositories into v, the
+// fields of opt are added to the query of u
+func (repo GithubRepository) repositoryRequest(method, u string, opt, body, v interface{}) (*github.Response, error) {
+	if opt != nil {
+		qs, err := query.Values(opt)
+		if err != nil {
+			return nil, err
+		}
+		u += "?" + qs.Encode()
+	}
+
+	req, err := repo.client.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaTypeTopicsPreview)
+
+	return repo.client.Do(repo.context, req, v)
+}
+
+// toDomainRepository is the only mapping of github repositories, every
+// repository returned by the service goes through it
+func toDomainRepository(rp *ghRepository) domain.Repository {
+	r := domain.Repository{
+		ID:            rp.ID,
+		Name:          rp.Name,
+		FullName:      rp.FullName,
+		Description:   rp.Description,
+		Private:       rp.Private,
+		HTMLURL:       rp.HTMLURL,
+		CloneURL:      rp.CloneURL,
+		SSHURL:        rp.SSHURL,
+		DefaultBranch: rp.DefaultBranch,
+		Language:      rp.Language,
+		Topics:        rp.Topics,
+		Fork:          rp.Fork,
+		Archived:      rp.Archived,
+		Size:          rp.Size,
+	}
+
+	if rp.Owner != nil {
+		r.Owner = rp.Owner.Login
+	}
+	if rp.CreatedAt != nil {
+		r.CreatedAt = &rp.CreatedAt.Time
+	}
+	if rp.PushedAt != nil {
+		r.PushedAt = &rp.PushedAt.Time
+	}
+	if rp.UpdatedAt != nil {
+		r.UpdatedAt = &rp.UpdatedAt.Time
+	}
+	if rp.Permissions != nil {
+		permissions := *rp.Permissions
+		r.Permissions = &domain.RepositoryPermissions{
+			Admin: permissions["admin"],
+			Push:  permissions["push"],
+			Pull:  permissions["pull"],
+		}
+	}
+
+	return r
+}
+
+// SetArchived archives or unarchives a repository
+func (repo GithubRepository) SetArchived(username, reponame string, archived bool) (*domain.Repository, error) {
+	u := fmt.Sprintf("repos/%v/%v", username, reponame)
+	rp := &ghRepository{}
+	_, err := repo.repositoryRequest("PATCH", u, nil, &repositoryEdit{Archived: github.Bool(archived)}, rp)
+	if err != nil {
+		return nil, err
+	}
+
+	r := toDomainRepository(rp)
+	return &r, nil
+}
+
+type transferRequest struct {
+	NewOwner string `json:"new_owner"`
+	TeamIDs  []int  `json:"team_ids,omitempty"`
+}
+
+// TransferRepo asks github to transfer a repository to a new owner, the transfer
+// is completed asynchronously
+func (repo GithubRepository) TransferRepo(username, reponame, newOwner string, teamIDs []int) error {
+	u := fmt.Sprintf("repos/%v/%v/transfer", username, reponame)
+	req, err := repo.client.NewRequest("POST", u, &transferRequest{NewOwner: newOwner, TeamIDs: teamIDs})
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github.nightshade-preview+json")
+
+	_, err = repo.client.Do(repo.context, req, nil)
+	if _, ok := err.(*github.AcceptedError); ok {
+		return nil
+	}
+	return err
+}
+
+type forkRequest struct {
+	Organization string `json:"organization,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// ForkRepo asks github to fork a repository into the user account or an organization,
+// the fork is created asynchronously. The returned repository is where github
+// puts the fork, which can have another name than the one requested or be an
+// existing fork.
+func (repo GithubRepository) ForkRepo(owner, reponame, org, name string) (*domain.Repository, error) {
+	u := fmt.Sprintf("repos/%v/%v/forks", owner, reponame)
+	req, err := repo.client.NewRequest("POST", u, &forkRequest{Organization: org, Name: name})
 	if err != nil {
 		return nil, err
 	}
 
-	r := &domain.Repository{
-		Name:        rp.Name,
-		FullName:    rp.FullName,
-		Description: rp.Description,
-		Private:     rp.Private,
-		HTMLURL:     rp.HTMLURL,
-		CloneURL:    rp.CloneURL,
-		SSHURL:      rp.SSHURL,
+	// The client of go-github discards the body of 202 responses, the fork is
+	// only described in it
+	resp, err := repo.httpClient.Do(req.WithContext(repo.context))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		err = github.CheckResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rp := &ghRepository{}
+	err = json.NewDecoder(resp.Body).Decode(rp)
+	if err != nil {
+		return nil, err
 	}
 
-	return r, nil
+	r := toDomainRepository(rp)
+	return &r, nil
 }
 
 // GetKey returns a Key from the user github account
@@ -207,24 +386,30 @@ func (repo GithubRepository) GetKey(username string, id int) (*domain.Key, error
 	return key, nil
 }
 
-// ShowKeys returns all the keys in a user github account
+// ShowKeys returns all the keys in the github account that owns the token, like
+// the other key methods
 func (repo GithubRepository) ShowKeys(username string) ([]domain.Key, error) {
-	ghKeys, _, err := repo.client.Users.ListKeys(repo.context, username, nil)
-	if err != nil {
-		fmt.Println(err.Error())
-		return nil, err
-	}
+	opt := &github.ListOptions{PerPage: 100}
 
 	keys := []domain.Key{}
-
-	for _, k := range ghKeys {
-		key := domain.Key{
-			ID:    k.ID,
-			Key:   k.Key,
-			Title: k.Title,
-			URL:   k.URL,
+	for {
+		ghKeys, resp, err := repo.client.Users.ListKeys(repo.context, "", opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range ghKeys {
+			key := domain.Key{
+				ID:    k.ID,
+				Key:   k.Key,
+				Title: k.Title,
+				URL:   k.URL,
+			}
+			keys = append(keys, key)
+		}
+		if resp.NextPage == 0 {
+			break
 		}
-		keys = append(keys, key)
+		opt.Page = resp.NextPage
 	}
 
 	return keys, nil
@@ -276,9 +461,21 @@ func (repo GithubRepository) AddFiles(files []domain.File, author domain.Author,
 	tree := []github.TreeEntry{}
 	emptyRepo := "409 Git Repository is empty"
 
+	// Commit to the default branch unless the author asks for another one
+	branch := author.Branch
+	if branch == "" {
+		rp, _, err := repo.client.Repositories.Get(repo.context, username, reponame)
+		if err != nil {
+			return err
+		}
+		branch = "master"
+		if rp.DefaultBranch != nil {
+			branch = *rp.DefaultBranch
+		}
+	}
+
 	// Get the reference sha
-	// TODO remove hardcoded branch name
-	ghTree, _, err := repo.client.Git.GetRef(repo.context, username, reponame, "heads/master")
+	ghTree, _, err := repo.client.Git.GetRef(repo.context, username, reponame, "heads/"+branch)
 	if err != nil {
 
 		// if the repo is empty create a README, else unexpected error
@@ -287,6 +484,7 @@ func (repo GithubRepository) AddFiles(files []domain.File, author domain.Author,
 			// Create a copy of author for the initial commit
 			author2 := author
 			author2.Message = "Initial Commit"
+			author2.Branch = branch
 
 			err = repo.CreateFile(README, author2, username, reponame)
 			if err != nil {
@@ -294,7 +492,7 @@ func (repo GithubRepository) AddFiles(files []domain.File, author domain.Author,
 			}
 
 			// Repository should not be empty, otherwise, unexpected error
-			ghTree, _, err = repo.client.Git.GetRef(repo.context, username, reponame, "heads/master")
+			ghTree, _, err = repo.client.Git.GetRef(repo.context, username, reponame, "heads/"+branch)
 			if err != nil {
 				return err
 			}
@@ -356,7 +554,7 @@ func (repo GithubRepository) AddFiles(files []domain.File, author domain.Author,
 	}
 
 	reference := github.Reference{
-		Ref: github.String("refs/heads/master"),
+		Ref: github.String("refs/heads/" + branch),
 		Object: &github.GitObject{
 			SHA: newCommit.SHA,
 		},
@@ -370,18 +568,6 @@ func (repo GithubRepository) AddFiles(files []domain.File, author domain.Author,
 	return nil
 }
 
-func (repo GithubRepository) AddDeployKey(username, reponame string, key *domain.Key) error {
-	private := true
-	k := github.Key{
-		Title:    key.Title,
-		Key:      key.Key,
-		ReadOnly: &private,
-	}
-
-	_, _, err := repo.client.Repositories.CreateKey(repo.context, username, reponame, &k)
-	return err
-}
-
 func randSeq(n int) string {
 	rand.Seed(time.Now().UTC().UnixNano())
 	b := make([]rune, n)
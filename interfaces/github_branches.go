@@ -0,0 +1,100 @@
+package interfaces
+
+import (
+	"fmt"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// ListBranches returns all the branches of a repository with their head SHA
+func (repo GithubRepository) ListBranches(username, reponame string) ([]domain.Branch, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	branches := []domain.Branch{}
+	for {
+		ghBranches, resp, err := repo.client.Repositories.ListBranches(repo.context, username, reponame, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range ghBranches {
+			branches = append(branches, toDomainBranch(b))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return branches, nil
+}
+
+// GetBranch returns a single branch of a repository
+func (repo GithubRepository) GetBranch(username, reponame, branch string) (*domain.Branch, error) {
+	b, _, err := repo.client.Repositories.GetBranch(repo.context, username, reponame, branch)
+	if isNotFound(err) {
+		return nil, domain.ErrBranchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	br := toDomainBranch(b)
+	return &br, nil
+}
+
+// CreateBranch creates a branch pointing to from, which can be a branch, a tag or a commit SHA
+func (repo GithubRepository) CreateBranch(username, reponame, branch, from string) (*domain.Branch, error) {
+	sha, _, err := repo.client.Repositories.GetCommitSHA1(repo.context, username, reponame, from, "")
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %s", from, err.Error())
+	}
+
+	ref := &github.Reference{
+		Ref: github.String("refs/heads/" + branch),
+		Object: &github.GitObject{
+			SHA: github.String(sha),
+		},
+	}
+
+	_, _, err = repo.client.Git.CreateRef(repo.context, username, reponame, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &domain.Branch{
+		Name: github.String(branch),
+		SHA:  github.String(sha),
+	}
+
+	return b, nil
+}
+
+// DeleteBranch removes a branch from a repository
+func (repo GithubRepository) DeleteBranch(username, reponame, branch string) error {
+	_, err := repo.client.Git.DeleteRef(repo.context, username, reponame, "heads/"+branch)
+	return err
+}
+
+// SetDefaultBranch changes the default branch of a repository
+func (repo GithubRepository) SetDefaultBranch(username, reponame, branch string) (*domain.Repository, error) {
+	u := fmt.Sprintf("repos/%v/%v", username, reponame)
+	rp := &ghRepository{}
+	_, err := repo.repositoryRequest("PATCH", u, nil, &repositoryEdit{DefaultBranch: github.String(branch)}, rp)
+	if err != nil {
+		return nil, err
+	}
+
+	r := toDomainRepository(rp)
+	return &r, nil
+}
+
+func toDomainBranch(b *github.Branch) domain.Branch {
+	br := domain.Branch{
+		Name: b.Name,
+	}
+	if b.Commit != nil {
+		br.SHA = b.Commit.SHA
+	}
+	return br
+}
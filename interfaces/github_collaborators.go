@@ -0,0 +1,153 @@
+package interfaces
+
+import (
+	"fmt"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+const mediaTypeInvitationsPreview = "application/vnd.github.swamp-thing-preview+json"
+
+// invitationPermissions maps the permissions of the invitations API to the
+// permissions used to add collaborators
+var invitationPermissions = map[string]string{
+	"read":  domain.PermissionPull,
+	"write": domain.PermissionPush,
+	"admin": domain.PermissionAdmin,
+}
+
+// ListCollaborators returns the collaborators of a repository with their permission
+func (repo GithubRepository) ListCollaborators(owner, reponame string) ([]domain.Collaborator, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	collaborators := []domain.Collaborator{}
+	for {
+		users, resp, err := repo.client.Repositories.ListCollaborators(repo.context, owner, reponame, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			collaborators = append(collaborators, toDomainCollaborator(u))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return collaborators, nil
+}
+
+// AddCollaborator grants a user a permission on a repository, github invites
+// users that are not collaborators yet and the invitation is returned
+func (repo GithubRepository) AddCollaborator(owner, reponame, user, permission string) (*domain.Invitation, error) {
+	u := fmt.Sprintf("repos/%v/%v/collaborators/%v", owner, reponame, user)
+	req, err := repo.client.NewRequest("PUT", u, &github.RepositoryAddCollaboratorOptions{Permission: permission})
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaTypeInvitationsPreview)
+
+	invitation := &github.RepositoryInvitation{}
+	_, err = repo.client.Do(repo.context, req, invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	// Existing collaborators get their permission updated without an invitation
+	if invitation.ID == nil {
+		return nil, nil
+	}
+
+	return toDomainInvitation(invitation), nil
+}
+
+// RemoveCollaborator removes the access of a user to a repository
+func (repo GithubRepository) RemoveCollaborator(owner, reponame, user string) error {
+	_, err := repo.client.Repositories.RemoveCollaborator(repo.context, owner, reponame, user)
+	return err
+}
+
+// ListInvitations returns the pending invitations of a repository
+func (repo GithubRepository) ListInvitations(owner, reponame string) ([]domain.Invitation, error) {
+	r, _, err := repo.client.Repositories.Get(repo.context, owner, reponame)
+	if err != nil {
+		return nil, err
+	}
+
+	opt := &github.ListOptions{PerPage: 100}
+
+	invitations := []domain.Invitation{}
+	for {
+		ghInvitations, resp, err := repo.client.Repositories.ListInvitations(repo.context, *r.ID, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range ghInvitations {
+			invitations = append(invitations, *toDomainInvitation(i))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return invitations, nil
+}
+
+// DeleteInvitation cancels a pending invitation
+func (repo GithubRepository) DeleteInvitation(owner, reponame string, id int) error {
+	r, _, err := repo.client.Repositories.Get(repo.context, owner, reponame)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.client.Repositories.DeleteInvitation(repo.context, *r.ID, id)
+	return err
+}
+
+func toDomainCollaborator(u *github.User) domain.Collaborator {
+	collaborator := domain.Collaborator{
+		ID:    u.ID,
+		Login: u.Login,
+	}
+
+	if u.Permissions != nil {
+		permissions := *u.Permissions
+		for _, p := range []string{domain.PermissionAdmin, domain.PermissionPush, domain.PermissionPull} {
+			if permissions[p] {
+				permission := p
+				collaborator.Permission = &permission
+				break
+			}
+		}
+	}
+
+	return collaborator
+}
+
+func toDomainInvitation(i *github.RepositoryInvitation) *domain.Invitation {
+	invitation := &domain.Invitation{
+		ID:      i.ID,
+		HTMLURL: i.HTMLURL,
+	}
+	if i.Invitee != nil {
+		invitation.Invitee = i.Invitee.Login
+	}
+	if i.Inviter != nil {
+		invitation.Inviter = i.Inviter.Login
+	}
+	if i.Permissions != nil {
+		permission := *i.Permissions
+		if p, ok := invitationPermissions[permission]; ok {
+			permission = p
+		}
+		invitation.Permission = &permission
+	}
+	if i.CreatedAt != nil {
+		invitation.CreatedAt = &i.CreatedAt.Time
+	}
+
+	return invitation
+}
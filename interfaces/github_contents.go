@@ -0,0 +1,53 @@
+package interfaces
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/github"
+)
+
+// GetTree returns the paths of every file of a repository at a ref
+func (repo GithubRepository) GetTree(owner, reponame, ref string) ([]string, error) {
+	tree, _, err := repo.client.Git.GetTree(repo.context, owner, reponame, ref, true)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, entry := range tree.Entries {
+		if entry.Type != nil && *entry.Type == "blob" && entry.Path != nil {
+			paths = append(paths, *entry.Path)
+		}
+	}
+
+	return paths, nil
+}
+
+// GetFile returns the content of a file of a repository at a ref
+func (repo GithubRepository) GetFile(owner, reponame, ref, path string) ([]byte, error) {
+	opt := &github.RepositoryContentGetOptions{Ref: ref}
+	file, _, _, err := repo.client.Repositories.GetContents(repo.context, owner, reponame, path, opt)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%s is not a file", path)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(content), nil
+}
+
+// ListLanguages returns the bytes of code of each language of a repository
+func (repo GithubRepository) ListLanguages(owner, reponame string) (map[string]int, error) {
+	languages, _, err := repo.client.Repositories.ListLanguages(repo.context, owner, reponame)
+	if err != nil {
+		return nil, err
+	}
+
+	return languages, nil
+}
@@ -0,0 +1,80 @@
+package interfaces
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// AddDeployKey registers a deploy key in a repository, the key is read only
+// unless ReadOnly is false. The ID and URL of the created key are set in key.
+func (repo GithubRepository) AddDeployKey(username, reponame string, key *domain.Key) error {
+	readOnly := true
+	if key.ReadOnly != nil {
+		readOnly = *key.ReadOnly
+	}
+
+	k := github.Key{
+		Title:    key.Title,
+		Key:      key.Key,
+		ReadOnly: &readOnly,
+	}
+
+	ghK, _, err := repo.client.Repositories.CreateKey(repo.context, username, reponame, &k)
+	if err != nil {
+		return err
+	}
+
+	key.ID = ghK.ID
+	key.URL = ghK.URL
+	key.ReadOnly = ghK.ReadOnly
+
+	return nil
+}
+
+// ListDeployKeys returns the deploy keys of a repository
+func (repo GithubRepository) ListDeployKeys(username, reponame string) ([]domain.Key, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	keys := []domain.Key{}
+	for {
+		ghKeys, resp, err := repo.client.Repositories.ListKeys(repo.context, username, reponame, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range ghKeys {
+			keys = append(keys, *toDomainKey(k))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return keys, nil
+}
+
+// GetDeployKey returns a deploy key of a repository
+func (repo GithubRepository) GetDeployKey(username, reponame string, id int) (*domain.Key, error) {
+	k, _, err := repo.client.Repositories.GetKey(repo.context, username, reponame, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainKey(k), nil
+}
+
+// DeleteDeployKey removes a deploy key from a repository
+func (repo GithubRepository) DeleteDeployKey(username, reponame string, id int) error {
+	_, err := repo.client.Repositories.DeleteKey(repo.context, username, reponame, id)
+	return err
+}
+
+func toDomainKey(k *github.Key) *domain.Key {
+	return &domain.Key{
+		ID:       k.ID,
+		Key:      k.Key,
+		Title:    k.Title,
+		URL:      k.URL,
+		ReadOnly: k.ReadOnly,
+	}
+}
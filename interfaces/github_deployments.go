@@ -0,0 +1,123 @@
+package interfaces
+
+import (
+	"fmt"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// The queued and in_progress states are only accepted with the flash preview
+const mediaTypeDeploymentsPreview = "application/vnd.github.ant-man-preview+json, application/vnd.github.flash-preview+json"
+
+// deploymentStatus adds the urls the vendored go-github does not read to a
+// deployment status
+type deploymentStatus struct {
+	github.DeploymentStatus
+	LogURL         *string `json:"log_url,omitempty"`
+	EnvironmentURL *string `json:"environment_url,omitempty"`
+}
+
+// CreateDeployment creates a deployment of a ref to an environment
+func (repo GithubRepository) CreateDeployment(username, reponame string, deployment *domain.Deployment) (*domain.Deployment, error) {
+	dr := &github.DeploymentRequest{
+		Ref:         deployment.Ref,
+		Task:        deployment.Task,
+		Environment: deployment.Environment,
+		Description: deployment.Description,
+		// Deploy the ref as it is instead of merging the default branch into it
+		AutoMerge:        github.Bool(false),
+		RequiredContexts: deployment.RequiredContexts,
+	}
+	if dr.RequiredContexts == nil {
+		// Github requires every status check of the ref to pass when the
+		// contexts are left out, a ref with pending checks could not be deployed
+		dr.RequiredContexts = &[]string{}
+	}
+
+	d, _, err := repo.client.Repositories.CreateDeployment(repo.context, username, reponame, dr)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainDeployment(d), nil
+}
+
+// ListDeployments returns the deployments of a repository, filtered by environment when given
+func (repo GithubRepository) ListDeployments(username, reponame, environment string) ([]domain.Deployment, error) {
+	opt := &github.DeploymentsListOptions{
+		Environment: environment,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	deployments := []domain.Deployment{}
+	for {
+		ghDeployments, resp, err := repo.client.Repositories.ListDeployments(repo.context, username, reponame, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range ghDeployments {
+			deployments = append(deployments, *toDomainDeployment(d))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return deployments, nil
+}
+
+// CreateDeploymentStatus posts a new state for a deployment
+func (repo GithubRepository) CreateDeploymentStatus(username, reponame string, id int, status *domain.DeploymentStatus) (*domain.DeploymentStatus, error) {
+	sr := &github.DeploymentStatusRequest{
+		State:          status.State,
+		Description:    status.Description,
+		LogURL:         status.LogURL,
+		EnvironmentURL: status.EnvironmentURL,
+	}
+
+	u := fmt.Sprintf("repos/%v/%v/deployments/%v/statuses", username, reponame, id)
+	req, err := repo.client.NewRequest("POST", u, sr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaTypeDeploymentsPreview)
+
+	s := &deploymentStatus{}
+	_, err = repo.client.Do(repo.context, req, s)
+	if err != nil {
+		return nil, err
+	}
+
+	ds := &domain.DeploymentStatus{
+		ID:             s.ID,
+		State:          s.State,
+		Description:    s.Description,
+		LogURL:         s.LogURL,
+		EnvironmentURL: s.EnvironmentURL,
+	}
+	if s.CreatedAt != nil {
+		ds.CreatedAt = &s.CreatedAt.Time
+	}
+
+	return ds, nil
+}
+
+func toDomainDeployment(d *github.Deployment) *domain.Deployment {
+	deployment := &domain.Deployment{
+		ID:          d.ID,
+		Ref:         d.Ref,
+		SHA:         d.SHA,
+		Task:        d.Task,
+		Environment: d.Environment,
+		Description: d.Description,
+	}
+	if d.Creator != nil {
+		deployment.Creator = d.Creator.Login
+	}
+	if d.CreatedAt != nil {
+		deployment.CreatedAt = &d.CreatedAt.Time
+	}
+	return deployment
+}
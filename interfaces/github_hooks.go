@@ -0,0 +1,130 @@
+package interfaces
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// The hook methods manage the hooks of the organization owner when reponame is empty
+
+// ListHooks returns the webhooks of a repository or an organization
+func (repo GithubRepository) ListHooks(owner, reponame string) ([]domain.Hook, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	hooks := []domain.Hook{}
+	for {
+		var ghHooks []*github.Hook
+		var resp *github.Response
+		var err error
+		if reponame == "" {
+			ghHooks, resp, err = repo.client.Organizations.ListHooks(repo.context, owner, opt)
+		} else {
+			ghHooks, resp, err = repo.client.Repositories.ListHooks(repo.context, owner, reponame, opt)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range ghHooks {
+			hooks = append(hooks, *toDomainHook(h))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return hooks, nil
+}
+
+// CreateHook registers a webhook in a repository or an organization
+func (repo GithubRepository) CreateHook(owner, reponame string, hook *domain.Hook) (*domain.Hook, error) {
+	var h *github.Hook
+	var err error
+	if reponame == "" {
+		h, _, err = repo.client.Organizations.CreateHook(repo.context, owner, fromDomainHook(hook))
+	} else {
+		h, _, err = repo.client.Repositories.CreateHook(repo.context, owner, reponame, fromDomainHook(hook))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainHook(h), nil
+}
+
+// EditHook updates the events and configuration of a webhook
+func (repo GithubRepository) EditHook(owner, reponame string, id int, hook *domain.Hook) (*domain.Hook, error) {
+	var h *github.Hook
+	var err error
+	if reponame == "" {
+		h, _, err = repo.client.Organizations.EditHook(repo.context, owner, id, fromDomainHook(hook))
+	} else {
+		h, _, err = repo.client.Repositories.EditHook(repo.context, owner, reponame, id, fromDomainHook(hook))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainHook(h), nil
+}
+
+// DeleteHook removes a webhook
+func (repo GithubRepository) DeleteHook(owner, reponame string, id int) error {
+	var err error
+	if reponame == "" {
+		_, err = repo.client.Organizations.DeleteHook(repo.context, owner, id)
+	} else {
+		_, err = repo.client.Repositories.DeleteHook(repo.context, owner, reponame, id)
+	}
+	return err
+}
+
+// PingHook asks github to send a ping event to a webhook
+func (repo GithubRepository) PingHook(owner, reponame string, id int) error {
+	var err error
+	if reponame == "" {
+		_, err = repo.client.Organizations.PingHook(repo.context, owner, id)
+	} else {
+		_, err = repo.client.Repositories.PingHook(repo.context, owner, reponame, id)
+	}
+	return err
+}
+
+// fromDomainHook only sets the configuration when the hook has a URL, github
+// replaces the whole configuration on edits
+func fromDomainHook(hook *domain.Hook) *github.Hook {
+	h := &github.Hook{
+		Name:   github.String("web"),
+		Events: hook.Events,
+		Active: hook.Active,
+	}
+
+	if hook.URL != nil {
+		h.Config = map[string]interface{}{
+			"url": *hook.URL,
+		}
+		if hook.ContentType != nil {
+			h.Config["content_type"] = *hook.ContentType
+		}
+		if hook.Secret != nil {
+			h.Config["secret"] = *hook.Secret
+		}
+	}
+
+	return h
+}
+
+func toDomainHook(h *github.Hook) *domain.Hook {
+	hook := &domain.Hook{
+		ID:     h.ID,
+		Events: h.Events,
+		Active: h.Active,
+	}
+	if url, ok := h.Config["url"].(string); ok {
+		hook.URL = github.String(url)
+	}
+	if contentType, ok := h.Config["content_type"].(string); ok {
+		hook.ContentType = github.String(contentType)
+	}
+	return hook
+}
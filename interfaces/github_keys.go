@@ -0,0 +1,63 @@
+package interfaces
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// The key methods manage the keys of the account that owns the token
+
+// DeleteKey removes a SSH key from the user github account
+func (repo GithubRepository) DeleteKey(username string, id int) error {
+	_, err := repo.client.Users.DeleteKey(repo.context, id)
+	return err
+}
+
+// ListGPGKeys returns the GPG keys of the user github account
+func (repo GithubRepository) ListGPGKeys(username string) ([]domain.GPGKey, error) {
+	ghKeys, _, err := repo.client.Users.ListGPGKeys(repo.context)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []domain.GPGKey{}
+	for _, k := range ghKeys {
+		keys = append(keys, *toDomainGPGKey(k))
+	}
+
+	return keys, nil
+}
+
+// CreateGPGKey adds an ASCII armored GPG public key to the user github account
+func (repo GithubRepository) CreateGPGKey(username, armoredPublicKey string) (*domain.GPGKey, error) {
+	k, _, err := repo.client.Users.CreateGPGKey(repo.context, armoredPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainGPGKey(k), nil
+}
+
+// DeleteGPGKey removes a GPG key from the user github account
+func (repo GithubRepository) DeleteGPGKey(username string, id int) error {
+	_, err := repo.client.Users.DeleteGPGKey(repo.context, id)
+	return err
+}
+
+func toDomainGPGKey(k *github.GPGKey) *domain.GPGKey {
+	key := &domain.GPGKey{
+		ID:        k.ID,
+		KeyID:     k.KeyID,
+		PublicKey: k.PublicKey,
+		CanSign:   k.CanSign,
+		CreatedAt: k.CreatedAt,
+		ExpiresAt: k.ExpiresAt,
+	}
+	for _, email := range k.Emails {
+		if email.Email != nil {
+			key.Emails = append(key.Emails, *email.Email)
+		}
+	}
+
+	return key
+}
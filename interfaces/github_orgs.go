@@ -0,0 +1,102 @@
+package interfaces
+
+import (
+	"fmt"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// ListOrganizations returns the organizations of the authenticated user with
+// the role of the user in each of them
+func (repo GithubRepository) ListOrganizations() ([]domain.Organization, error) {
+	opt := &github.ListOrgMembershipsOptions{
+		State:       "active",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	orgs := []domain.Organization{}
+	for {
+		memberships, resp, err := repo.client.Organizations.ListOrgMemberships(repo.context, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range memberships {
+			org := domain.Organization{Role: m.Role}
+			if m.Organization != nil {
+				org.ID = m.Organization.ID
+				org.Login = m.Organization.Login
+				org.Description = m.Organization.Description
+			}
+			orgs = append(orgs, org)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return orgs, nil
+}
+
+// ListOrgRepos returns the repositories of an organization the user can see
+func (repo GithubRepository) ListOrgRepos(org string) ([]domain.Repository, error) {
+	u := fmt.Sprintf("orgs/%v/repos", org)
+	opt := &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	repos := []domain.Repository{}
+	for {
+		rps := []*ghRepository{}
+		resp, err := repo.repositoryRequest("GET", u, opt, nil, &rps)
+		if err != nil {
+			return nil, err
+		}
+		for _, rp := range rps {
+			repos = append(repos, toDomainRepository(rp))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+// ListTeams returns the teams of an organization
+func (repo GithubRepository) ListTeams(org string) ([]domain.Team, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	teams := []domain.Team{}
+	for {
+		ghTeams, resp, err := repo.client.Organizations.ListTeams(repo.context, org, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range ghTeams {
+			teams = append(teams, domain.Team{
+				ID:          t.ID,
+				Name:        t.Name,
+				Slug:        t.Slug,
+				Description: t.Description,
+				Privacy:     t.Privacy,
+				Permission:  t.Permission,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return teams, nil
+}
+
+// AddTeamRepo grants a team a permission on a repository of its organization
+func (repo GithubRepository) AddTeamRepo(team int, owner, reponame, permission string) error {
+	opt := &github.OrganizationAddTeamRepoOptions{Permission: permission}
+	_, err := repo.client.Organizations.AddTeamRepo(repo.context, team, owner, reponame, opt)
+	return err
+}
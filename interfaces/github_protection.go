@@ -0,0 +1,109 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// The protection schema in the vendored go-github predates the current API,
+// so requests are built by hand
+const mediaTypeProtectionPreview = "application/vnd.github.luke-cage-preview+json"
+
+type protectionRequest struct {
+	RequiredStatusChecks       *domain.RequiredStatusChecks `json:"required_status_checks"`
+	EnforceAdmins              bool                         `json:"enforce_admins"`
+	RequiredPullRequestReviews *domain.RequiredReviews      `json:"required_pull_request_reviews"`
+	Restrictions               *domain.PushRestrictions     `json:"restrictions"`
+}
+
+type protection struct {
+	RequiredStatusChecks *domain.RequiredStatusChecks `json:"required_status_checks"`
+	EnforceAdmins        *struct {
+		Enabled bool `json:"enabled"`
+	} `json:"enforce_admins"`
+	RequiredPullRequestReviews *domain.RequiredReviews `json:"required_pull_request_reviews"`
+	Restrictions               *struct {
+		Users []struct {
+			Login string `json:"login"`
+		} `json:"users"`
+		Teams []struct {
+			Slug string `json:"slug"`
+		} `json:"teams"`
+	} `json:"restrictions"`
+}
+
+// GetBranchProtection returns the protection policy of a branch, an unprotected
+// branch returns an empty policy
+func (repo GithubRepository) GetBranchProtection(username, reponame, branch string) (*domain.BranchProtection, error) {
+	u := fmt.Sprintf("repos/%v/%v/branches/%v/protection", username, reponame, branch)
+	req, err := repo.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaTypeProtectionPreview)
+
+	p := &protection{}
+	_, err = repo.client.Do(repo.context, req, p)
+	if err != nil {
+		if e, ok := err.(*github.ErrorResponse); ok && e.Response.StatusCode == http.StatusNotFound && e.Message == "Branch not protected" {
+			return &domain.BranchProtection{}, nil
+		}
+		return nil, err
+	}
+
+	return toDomainProtection(p), nil
+}
+
+// UpdateBranchProtection replaces the protection policy of a branch
+func (repo GithubRepository) UpdateBranchProtection(username, reponame, branch string, policy *domain.BranchProtection) (*domain.BranchProtection, error) {
+	preq := &protectionRequest{
+		RequiredStatusChecks:       policy.RequiredStatusChecks,
+		EnforceAdmins:              policy.EnforceAdmins,
+		RequiredPullRequestReviews: policy.RequiredReviews,
+		Restrictions:               policy.Restrictions,
+	}
+
+	u := fmt.Sprintf("repos/%v/%v/branches/%v/protection", username, reponame, branch)
+	req, err := repo.client.NewRequest("PUT", u, preq)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", mediaTypeProtectionPreview)
+
+	p := &protection{}
+	_, err = repo.client.Do(repo.context, req, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainProtection(p), nil
+}
+
+func toDomainProtection(p *protection) *domain.BranchProtection {
+	policy := &domain.BranchProtection{
+		RequiredStatusChecks: p.RequiredStatusChecks,
+		RequiredReviews:      p.RequiredPullRequestReviews,
+	}
+
+	if p.EnforceAdmins != nil {
+		policy.EnforceAdmins = p.EnforceAdmins.Enabled
+	}
+
+	if p.Restrictions != nil {
+		policy.Restrictions = &domain.PushRestrictions{
+			Users: []string{},
+			Teams: []string{},
+		}
+		for _, u := range p.Restrictions.Users {
+			policy.Restrictions.Users = append(policy.Restrictions.Users, u.Login)
+		}
+		for _, t := range p.Restrictions.Teams {
+			policy.Restrictions.Teams = append(policy.Restrictions.Teams, t.Slug)
+		}
+	}
+
+	return policy
+}
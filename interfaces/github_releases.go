@@ -0,0 +1,168 @@
+package interfaces
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// CreateTag creates a lightweight tag, or an annotated one when the tag has a message,
+// pointing to the commit the tag target resolves to
+func (repo GithubRepository) CreateTag(username, reponame string, tag *domain.Tag) error {
+	sha, _, err := repo.client.Repositories.GetCommitSHA1(repo.context, username, reponame, tag.Target, "")
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s: %s", tag.Target, err.Error())
+	}
+
+	// A lightweight tag is only a reference to the commit
+	refSHA := sha
+
+	if tag.Message != "" {
+		now := time.Now()
+		t := &github.Tag{
+			Tag:     github.String(tag.Name),
+			Message: github.String(tag.Message),
+			Object: &github.GitObject{
+				SHA:  github.String(sha),
+				Type: github.String("commit"),
+			},
+			Tagger: &github.CommitAuthor{
+				Date:  &now,
+				Name:  github.String(tag.Tagger),
+				Email: github.String(tag.Email),
+			},
+		}
+
+		ghTag, _, err := repo.client.Git.CreateTag(repo.context, username, reponame, t)
+		if err != nil {
+			return err
+		}
+		refSHA = *ghTag.SHA
+	}
+
+	ref := &github.Reference{
+		Ref: github.String("refs/tags/" + tag.Name),
+		Object: &github.GitObject{
+			SHA: github.String(refSHA),
+		},
+	}
+
+	_, _, err = repo.client.Git.CreateRef(repo.context, username, reponame, ref)
+	if err != nil {
+		return err
+	}
+
+	tag.SHA = sha
+	return nil
+}
+
+// ListReleases returns all the releases of a repository, drafts included
+func (repo GithubRepository) ListReleases(username, reponame string) ([]domain.Release, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	releases := []domain.Release{}
+	for {
+		ghReleases, resp, err := repo.client.Repositories.ListReleases(repo.context, username, reponame, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range ghReleases {
+			releases = append(releases, *toDomainRelease(r))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return releases, nil
+}
+
+// CreateRelease creates a release, draft and prerelease flags are taken from the release
+func (repo GithubRepository) CreateRelease(username, reponame string, release *domain.Release) (*domain.Release, error) {
+	r, _, err := repo.client.Repositories.CreateRelease(repo.context, username, reponame, fromDomainRelease(release))
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainRelease(r), nil
+}
+
+// UpdateRelease edits the fields set in release, publishing a draft is done
+// by setting draft to false
+func (repo GithubRepository) UpdateRelease(username, reponame string, id int, release *domain.Release) (*domain.Release, error) {
+	r, _, err := repo.client.Repositories.EditRelease(repo.context, username, reponame, id, fromDomainRelease(release))
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainRelease(r), nil
+}
+
+// UploadReleaseAsset streams content to github as a new asset of a release,
+// github requires the size of the content to be known in advance
+func (repo GithubRepository) UploadReleaseAsset(username, reponame string, id int, name, label, contentType string, size int64, content io.Reader) (*domain.ReleaseAsset, error) {
+	u := fmt.Sprintf("repos/%s/%s/releases/%d/assets?name=%s", username, reponame, id, url.QueryEscape(name))
+	if label != "" {
+		u += "&label=" + url.QueryEscape(label)
+	}
+
+	req, err := repo.client.NewUploadRequest(u, content, size, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &github.ReleaseAsset{}
+	_, err = repo.client.Do(repo.context, req, a)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := toDomainReleaseAsset(*a)
+	return &asset, nil
+}
+
+func fromDomainRelease(r *domain.Release) *github.RepositoryRelease {
+	return &github.RepositoryRelease{
+		TagName:         r.TagName,
+		TargetCommitish: r.TargetCommitish,
+		Name:            r.Name,
+		Body:            r.Body,
+		Draft:           r.Draft,
+		Prerelease:      r.Prerelease,
+	}
+}
+
+func toDomainRelease(r *github.RepositoryRelease) *domain.Release {
+	release := &domain.Release{
+		ID:              r.ID,
+		TagName:         r.TagName,
+		TargetCommitish: r.TargetCommitish,
+		Name:            r.Name,
+		Body:            r.Body,
+		Draft:           r.Draft,
+		Prerelease:      r.Prerelease,
+		HTMLURL:         r.HTMLURL,
+	}
+
+	for _, a := range r.Assets {
+		release.Assets = append(release.Assets, toDomainReleaseAsset(a))
+	}
+
+	return release
+}
+
+func toDomainReleaseAsset(a github.ReleaseAsset) domain.ReleaseAsset {
+	return domain.ReleaseAsset{
+		ID:          a.ID,
+		Name:        a.Name,
+		Label:       a.Label,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		DownloadURL: a.BrowserDownloadURL,
+	}
+}
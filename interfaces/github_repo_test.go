@@ -9,6 +9,7 @@ import (
 
 	"net/url"
 
+	"github.com/Tinker-Ware/gh-service/domain"
 	. "github.com/Tinker-Ware/gh-service/interfaces"
 	"github.com/google/go-github/github"
 	"golang.org/x/oauth2"
@@ -60,10 +61,10 @@ var _ = Describe("GithubRepo", func() {
 			})
 
 			It("Should retrieve a list of repositories", func() {
-				repos, err := repo.GetAllRepos(username)
+				page, err := repo.ListRepos("", domain.RepositoryListOptions{})
 				Ω(err).ShouldNot(HaveOccurred())
 
-				Ω(repos).ShouldNot(HaveLen(0))
+				Ω(page.Repositories).ShouldNot(HaveLen(0))
 			})
 
 		})
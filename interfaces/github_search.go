@@ -0,0 +1,88 @@
+package interfaces
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// repositorySearchResult decodes the repositories found by a search with the
+// fields missing in github.Repository
+type repositorySearchResult struct {
+	Total             *int            `json:"total_count,omitempty"`
+	IncompleteResults *bool           `json:"incomplete_results,omitempty"`
+	Repositories      []*ghRepository `json:"items,omitempty"`
+}
+
+// SearchRepos returns a page of the repositories matching a github search query
+func (repo GithubRepository) SearchRepos(query string, opt domain.SearchOptions) (*domain.RepositorySearch, error) {
+	searchOpt := &struct {
+		Query string `url:"q"`
+		github.SearchOptions
+	}{query, *toSearchOptions(opt)}
+
+	result := &repositorySearchResult{}
+	resp, err := repo.repositoryRequest("GET", "search/repositories", searchOpt, nil, result)
+	if err != nil {
+		return nil, err
+	}
+
+	search := &domain.RepositorySearch{
+		Pages:        toDomainPages(resp),
+		Repositories: []domain.Repository{},
+	}
+	if result.Total != nil {
+		search.Total = *result.Total
+	}
+	if result.IncompleteResults != nil {
+		search.Incomplete = *result.IncompleteResults
+	}
+	for _, rp := range result.Repositories {
+		search.Repositories = append(search.Repositories, toDomainRepository(rp))
+	}
+
+	return search, nil
+}
+
+// SearchCode returns a page of the files matching a github search query
+func (repo GithubRepository) SearchCode(query string, opt domain.SearchOptions) (*domain.CodeSearch, error) {
+	result, resp, err := repo.client.Search.Code(repo.context, query, toSearchOptions(opt))
+	if err != nil {
+		return nil, err
+	}
+
+	search := &domain.CodeSearch{
+		Pages:   toDomainPages(resp),
+		Results: []domain.CodeResult{},
+	}
+	if result.Total != nil {
+		search.Total = *result.Total
+	}
+	if result.IncompleteResults != nil {
+		search.Incomplete = *result.IncompleteResults
+	}
+	for _, c := range result.CodeResults {
+		code := domain.CodeResult{
+			Name:    c.Name,
+			Path:    c.Path,
+			SHA:     c.SHA,
+			HTMLURL: c.HTMLURL,
+		}
+		if c.Repository != nil {
+			code.Repository = c.Repository.FullName
+		}
+		search.Results = append(search.Results, code)
+	}
+
+	return search, nil
+}
+
+func toSearchOptions(opt domain.SearchOptions) *github.SearchOptions {
+	return &github.SearchOptions{
+		Sort:  opt.Sort,
+		Order: opt.Order,
+		ListOptions: github.ListOptions{
+			Page:    opt.Page,
+			PerPage: opt.PerPage,
+		},
+	}
+}
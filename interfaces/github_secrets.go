@@ -0,0 +1,102 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+type secretsResponse struct {
+	TotalCount int             `json:"total_count"`
+	Secrets    []domain.Secret `json:"secrets"`
+}
+
+// secretsURL returns the URL of the Actions secrets of a repository, or of one
+// of its environments when environment is not empty
+func secretsURL(owner, reponame, environment string) string {
+	if environment == "" {
+		return fmt.Sprintf("repos/%v/%v/actions/secrets", owner, reponame)
+	}
+	return fmt.Sprintf("repos/%v/%v/environments/%v/secrets", owner, reponame, url.PathEscape(environment))
+}
+
+// GetSecretsPublicKey returns the key to encrypt the secrets of a repository or
+// of one of its environments
+func (repo GithubRepository) GetSecretsPublicKey(owner, reponame, environment string) (*domain.SecretsPublicKey, error) {
+	req, err := repo.client.NewRequest("GET", secretsURL(owner, reponame, environment)+"/public-key", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &domain.SecretsPublicKey{}
+	_, err = repo.client.Do(repo.context, req, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// ListSecrets returns the names of the secrets of a repository or of one of
+// its environments
+func (repo GithubRepository) ListSecrets(owner, reponame, environment string) ([]domain.Secret, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	secrets := []domain.Secret{}
+	for {
+		u := fmt.Sprintf("%s?per_page=%d", secretsURL(owner, reponame, environment), opt.PerPage)
+		if opt.Page > 0 {
+			u = fmt.Sprintf("%s&page=%d", u, opt.Page)
+		}
+		req, err := repo.client.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		page := &secretsResponse{}
+		resp, err := repo.client.Do(repo.context, req, page)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, page.Secrets...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return secrets, nil
+}
+
+// PutSecret creates or updates an encrypted secret, it returns true when the
+// secret is created
+func (repo GithubRepository) PutSecret(owner, reponame, environment, name string, secret domain.EncryptedSecret) (bool, error) {
+	u := fmt.Sprintf("%s/%v", secretsURL(owner, reponame, environment), url.PathEscape(name))
+	req, err := repo.client.NewRequest("PUT", u, &secret)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := repo.client.Do(repo.context, req, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return resp.StatusCode == http.StatusCreated, nil
+}
+
+// DeleteSecret removes a secret of a repository or of one of its environments
+func (repo GithubRepository) DeleteSecret(owner, reponame, environment, name string) error {
+	u := fmt.Sprintf("%s/%v", secretsURL(owner, reponame, environment), url.PathEscape(name))
+	req, err := repo.client.NewRequest("DELETE", u, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.client.Do(repo.context, req, nil)
+	return err
+}
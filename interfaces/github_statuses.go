@@ -0,0 +1,65 @@
+package interfaces
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+// CreateStatus marks a commit with the state of a check
+func (repo GithubRepository) CreateStatus(username, reponame, sha string, status *domain.CommitStatus) (*domain.CommitStatus, error) {
+	st := &github.RepoStatus{
+		State:       status.State,
+		Context:     status.Context,
+		Description: status.Description,
+		TargetURL:   status.TargetURL,
+	}
+
+	s, _, err := repo.client.Repositories.CreateStatus(repo.context, username, reponame, sha, st)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := toDomainStatus(*s)
+	return &cs, nil
+}
+
+// GetCombinedStatus returns the combined status of a ref, which can be a SHA,
+// a branch or a tag
+func (repo GithubRepository) GetCombinedStatus(username, reponame, ref string) (*domain.CombinedStatus, error) {
+	opt := &github.ListOptions{PerPage: 100}
+
+	combined := &domain.CombinedStatus{
+		Statuses: []domain.CommitStatus{},
+	}
+	for {
+		s, resp, err := repo.client.Repositories.GetCombinedStatus(repo.context, username, reponame, ref, opt)
+		if err != nil {
+			return nil, err
+		}
+
+		combined.State = s.State
+		combined.SHA = s.SHA
+		combined.TotalCount = s.TotalCount
+		for _, st := range s.Statuses {
+			combined.Statuses = append(combined.Statuses, toDomainStatus(st))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return combined, nil
+}
+
+func toDomainStatus(s github.RepoStatus) domain.CommitStatus {
+	return domain.CommitStatus{
+		ID:          s.ID,
+		State:       s.State,
+		Context:     s.Context,
+		Description: s.Description,
+		TargetURL:   s.TargetURL,
+		CreatedAt:   s.CreatedAt,
+	}
+}
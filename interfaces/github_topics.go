@@ -0,0 +1,33 @@
+package interfaces
+
+import "fmt"
+
+type topicNames struct {
+	Names []string `json:"names"`
+}
+
+// GetTopics returns the topics of a repository
+func (repo GithubRepository) GetTopics(owner, reponame string) ([]string, error) {
+	u := fmt.Sprintf("repos/%v/%v/topics", owner, reponame)
+
+	topics := &topicNames{}
+	_, err := repo.repositoryRequest("GET", u, nil, nil, topics)
+	if err != nil {
+		return nil, err
+	}
+
+	return topics.Names, nil
+}
+
+// ReplaceTopics replaces every topic of a repository and returns the new ones
+func (repo GithubRepository) ReplaceTopics(owner, reponame string, topics []string) ([]string, error) {
+	u := fmt.Sprintf("repos/%v/%v/topics", owner, reponame)
+
+	replaced := &topicNames{}
+	_, err := repo.repositoryRequest("PUT", u, nil, &topicNames{Names: topics}, replaced)
+	if err != nil {
+		return nil, err
+	}
+
+	return replaced.Names, nil
+}
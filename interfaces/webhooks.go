@@ -0,0 +1,213 @@
+package interfaces
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/google/go-github/github"
+)
+
+const (
+	signatureHeader       = "X-Hub-Signature"
+	signature256Header    = "X-Hub-Signature-256"
+	eventTypeHeader       = "X-GitHub-Event"
+	deliveryIDHeader      = "X-GitHub-Delivery"
+	hookIDHeader          = "X-GitHub-Hook-ID"
+	maxWebhookPayloadSize = 25 << 20
+)
+
+// WebhookDispatcher delivers verified webhook events to the in-process handlers
+// and keeps the deliveries so they can be replayed
+type WebhookDispatcher interface {
+	Record(delivery domain.Delivery) error
+	Dispatch(event domain.WebhookEvent) (bool, error)
+	Redispatch(event domain.WebhookEvent) error
+	ShowDeliveries(from, to time.Time) ([]domain.Delivery, error)
+	ShowDelivery(id string) (*domain.Delivery, error)
+}
+
+// DeployKeyEvent is triggered when a deploy key is added to or removed from a
+// repository, the vendored go-github does not know this event
+type DeployKeyEvent struct {
+	Action *string            `json:"action,omitempty"`
+	Key    *github.Key        `json:"key,omitempty"`
+	Repo   *github.Repository `json:"repository,omitempty"`
+	Sender *github.User       `json:"sender,omitempty"`
+}
+
+type webhookResponse struct {
+	DeliveryID string `json:"delivery_id"`
+	Duplicate  bool   `json:"duplicate"`
+}
+
+// ReceiveGithubEvent verifies the signature of a github webhook delivery against
+// the configured secrets and dispatches the parsed event
+func (handler WebServiceHandler) ReceiveGithubEvent(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(res, req.Body, maxWebhookPayloadSize))
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Cannot read payload: %s", err.Error()))
+		return
+	}
+
+	err = verifySignature(req.Header, payload, handler.secretsFor(req))
+	if err != nil {
+		writeError(res, http.StatusUnauthorized, fmt.Sprintf("Invalid webhook signature: %s", err.Error()))
+		return
+	}
+
+	deliveryID := req.Header.Get(deliveryIDHeader)
+	eventType := req.Header.Get(eventTypeHeader)
+	if deliveryID == "" || eventType == "" {
+		writeError(res, http.StatusBadRequest, "Missing webhook delivery headers")
+		return
+	}
+
+	event, parseErr := parseEvent(deliveryID, eventType, payload)
+
+	delivery := domain.Delivery{
+		ID:         deliveryID,
+		Event:      eventType,
+		Headers:    req.Header,
+		Payload:    payload,
+		Result:     domain.DeliveryReceived,
+		ReceivedAt: time.Now().UTC(),
+	}
+	if parseErr != nil {
+		delivery.Result = domain.DeliveryInvalid
+		delivery.Error = parseErr.Error()
+	}
+	if !json.Valid(payload) {
+		// Keep the body as a JSON string so the delivery can still be stored
+		delivery.Payload, _ = json.Marshal(string(payload))
+	}
+
+	// A delivery that cannot be stored is still dispatched
+	err = handler.Webhooks.Record(delivery)
+	if err != nil {
+		log.Printf("Cannot store delivery %s: %s", deliveryID, err.Error())
+	}
+
+	if parseErr != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Cannot parse %s event: %s", eventType, parseErr.Error()))
+		return
+	}
+
+	dispatched, err := handler.Webhooks.Dispatch(event)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot process delivery %s: %s", deliveryID, err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, webhookResponse{DeliveryID: deliveryID, Duplicate: !dispatched})
+}
+
+// secretsFor returns the configured secrets and the stored secret of the hook
+// that sent the delivery, when it was registered through the service
+func (handler WebServiceHandler) secretsFor(req *http.Request) []string {
+	secrets := append([]string{}, handler.WebhookSecrets...)
+	if handler.GHInteractor == nil {
+		return secrets
+	}
+
+	id, err := strconv.Atoi(req.Header.Get(hookIDHeader))
+	if err != nil {
+		return secrets
+	}
+
+	secret, err := handler.GHInteractor.ShowHookSecret(id)
+	if err == nil {
+		secrets = append(secrets, secret)
+	}
+
+	return secrets
+}
+
+// verifySignature checks the HMAC of the payload against every secret, the
+// SHA256 signature is preferred when github sends it
+func verifySignature(header http.Header, payload []byte, secrets []string) error {
+	hashFunc := sha256.New
+	prefix := "sha256="
+	signature := header.Get(signature256Header)
+	if signature == "" {
+		hashFunc = sha1.New
+		prefix = "sha1="
+		signature = header.Get(signatureHeader)
+	}
+
+	if !strings.HasPrefix(signature, prefix) {
+		return errors.New("missing signature")
+	}
+
+	mac, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+
+	for _, secret := range secrets {
+		if hmac.Equal(mac, payloadMAC(hashFunc, secret, payload)) {
+			return nil
+		}
+	}
+
+	return errors.New("signature does not match")
+}
+
+func payloadMAC(hashFunc func() hash.Hash, secret string, payload []byte) []byte {
+	mac := hmac.New(hashFunc, []byte(secret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseEvent converts the payload into the go-github type of the event, events
+// go-github does not know keep their raw JSON
+func parseEvent(deliveryID, eventType string, payload []byte) (event domain.WebhookEvent, err error) {
+	event = domain.WebhookEvent{
+		DeliveryID: deliveryID,
+		Type:       eventType,
+	}
+
+	// go-github panics when the payload does not unmarshal into the event type
+	if !json.Valid(payload) {
+		return event, errors.New("invalid JSON payload")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			event.Payload = nil
+			err = fmt.Errorf("invalid %s payload: %v", eventType, r)
+		}
+	}()
+
+	switch eventType {
+	case "deploy_key":
+		e := &DeployKeyEvent{}
+		err := json.Unmarshal(payload, e)
+		if err != nil {
+			return event, err
+		}
+		event.Payload = e
+	default:
+		e, err := github.ParseWebHook(eventType, payload)
+		if err != nil {
+			event.Payload = json.RawMessage(payload)
+			return event, nil
+		}
+		event.Payload = e
+	}
+
+	return event, nil
+}
@@ -0,0 +1,381 @@
+package interfaces_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/Tinker-Ware/gh-service/infrastructure"
+	. "github.com/Tinker-Ware/gh-service/interfaces"
+	"github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+	"github.com/gorilla/mux"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// resultlessStore stores deliveries when they are received and fails to store
+// their results
+type resultlessStore struct {
+	usecases.DeliveryStore
+}
+
+func (store resultlessStore) SaveDelivery(delivery domain.Delivery) error {
+	if delivery.Result != domain.DeliveryReceived {
+		return errors.New("disk full")
+	}
+	return store.DeliveryStore.SaveDelivery(delivery)
+}
+
+var _ = Describe("Webhooks", func() {
+	secret := "webhooksecret"
+	var received []domain.WebhookEvent
+	var handler WebServiceHandler
+
+	BeforeEach(func() {
+		received = []domain.WebhookEvent{}
+		dispatcher := usecases.NewWebhookDispatcher()
+		dispatcher.Handle(usecases.AllEvents, func(event domain.WebhookEvent) error {
+			received = append(received, event)
+			return nil
+		})
+
+		handler = WebServiceHandler{
+			WebhookSecrets: []string{"oldsecret", secret},
+			Webhooks:       dispatcher,
+		}
+	})
+
+	deliver := func(event, delivery, payloadFile string, sign func(req *http.Request, payload []byte)) *httptest.ResponseRecorder {
+		payload, err := ioutil.ReadFile("testdata/webhooks/" + payloadFile)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		req := httptest.NewRequest("POST", "/webhooks/github", bytes.NewReader(payload))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-GitHub-Delivery", delivery)
+		sign(req, payload)
+
+		res := httptest.NewRecorder()
+		handler.ReceiveGithubEvent(res, req)
+		return res
+	}
+
+	signSHA1 := func(req *http.Request, payload []byte) {
+		mac := hmac.New(sha1.New, []byte(secret))
+		mac.Write(payload)
+		req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	signSHA256 := func(req *http.Request, payload []byte) {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	Describe("Verify signatures", func() {
+		It("Should accept a payload signed with SHA1", func() {
+			res := deliver("ping", "d-1", "ping.json", signSHA1)
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(received).Should(HaveLen(1))
+		})
+
+		It("Should accept a payload signed with SHA256", func() {
+			res := deliver("ping", "d-1", "ping.json", signSHA256)
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(received).Should(HaveLen(1))
+		})
+
+		It("Should accept a payload signed with the secret of a registered hook", func() {
+			dir, err := ioutil.TempDir("", "hooks")
+			Ω(err).ShouldNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+			store, err := infrastructure.NewHookSecretStore(dir)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(store.SaveHookSecret(12, "hooksecret")).Should(Succeed())
+			handler.GHInteractor = usecases.GHInteractor{HookSecrets: store}
+
+			sign := func(hookID string) func(req *http.Request, payload []byte) {
+				return func(req *http.Request, payload []byte) {
+					mac := hmac.New(sha1.New, []byte("hooksecret"))
+					mac.Write(payload)
+					req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+					req.Header.Set("X-GitHub-Hook-ID", hookID)
+				}
+			}
+
+			res := deliver("push", "d-1", "push.json", sign("12"))
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(received).Should(HaveLen(1))
+
+			// The secret of a hook does not verify the deliveries of another one
+			res = deliver("push", "d-2", "push.json", sign("13"))
+			Ω(res.Code).Should(Equal(http.StatusUnauthorized))
+		})
+
+		It("Should reject a payload signed with an unknown secret", func() {
+			res := deliver("ping", "d-1", "ping.json", func(req *http.Request, payload []byte) {
+				mac := hmac.New(sha1.New, []byte("wrong"))
+				mac.Write(payload)
+				req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+			})
+			Ω(res.Code).Should(Equal(http.StatusUnauthorized))
+			Ω(received).Should(BeEmpty())
+		})
+
+		It("Should reject an unsigned payload", func() {
+			res := deliver("ping", "d-1", "ping.json", func(req *http.Request, payload []byte) {})
+			Ω(res.Code).Should(Equal(http.StatusUnauthorized))
+			Ω(received).Should(BeEmpty())
+		})
+	})
+
+	Describe("Parse events", func() {
+		It("Should parse a push event", func() {
+			res := deliver("push", "d-1", "push.json", signSHA1)
+			Ω(res.Code).Should(Equal(http.StatusOK))
+
+			Ω(received).Should(HaveLen(1))
+			Ω(received[0].DeliveryID).Should(Equal("d-1"))
+			push, ok := received[0].Payload.(*github.PushEvent)
+			Ω(ok).Should(BeTrue())
+			Ω(*push.Ref).Should(Equal("refs/heads/master"))
+			Ω(*push.Repo.FullName).Should(Equal("iasstest/test"))
+		})
+
+		It("Should parse a deploy key event", func() {
+			deliver("deploy_key", "d-1", "deploy_key.json", signSHA1)
+
+			Ω(received).Should(HaveLen(1))
+			key, ok := received[0].Payload.(*DeployKeyEvent)
+			Ω(ok).Should(BeTrue())
+			Ω(*key.Action).Should(Equal("created"))
+			Ω(*key.Key.Title).Should(Equal("provisioning"))
+		})
+
+		It("Should parse a repository deletion", func() {
+			deliver("repository", "d-1", "repository_deleted.json", signSHA1)
+
+			Ω(received).Should(HaveLen(1))
+			repo, ok := received[0].Payload.(*github.RepositoryEvent)
+			Ω(ok).Should(BeTrue())
+			Ω(*repo.Action).Should(Equal("deleted"))
+		})
+	})
+
+	Describe("Deduplicate deliveries", func() {
+		It("Should dispatch a delivery only once", func() {
+			deliver("push", "d-1", "push.json", signSHA1)
+			res := deliver("push", "d-1", "push.json", signSHA1)
+
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(res.Body.String()).Should(ContainSubstring(`"duplicate":true`))
+			Ω(received).Should(HaveLen(1))
+
+			deliver("push", "d-2", "push.json", signSHA1)
+			Ω(received).Should(HaveLen(2))
+		})
+
+		It("Should dispatch a failed delivery again", func() {
+			failures := 1
+			dispatcher := usecases.NewWebhookDispatcher()
+			dispatcher.Handle("push", func(event domain.WebhookEvent) error {
+				if failures > 0 {
+					failures--
+					return errors.New("consumer is down")
+				}
+				received = append(received, event)
+				return nil
+			})
+			handler.Webhooks = dispatcher
+
+			res := deliver("push", "d-1", "push.json", signSHA1)
+			Ω(res.Code).Should(Equal(http.StatusInternalServerError))
+
+			res = deliver("push", "d-1", "push.json", signSHA1)
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(received).Should(HaveLen(1))
+		})
+
+		It("Should not dispatch a delivery again when its result cannot be stored", func() {
+			dir, err := ioutil.TempDir("", "deliveries")
+			Ω(err).ShouldNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+			store, err := infrastructure.NewDeliveryStore(dir)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			dispatcher := usecases.NewWebhookDispatcher()
+			dispatcher.Store = resultlessStore{store}
+			dispatcher.Handle("push", func(event domain.WebhookEvent) error {
+				received = append(received, event)
+				return nil
+			})
+			handler.Webhooks = dispatcher
+
+			res := deliver("push", "d-1", "push.json", signSHA1)
+			Ω(res.Code).Should(Equal(http.StatusOK))
+
+			res = deliver("push", "d-1", "push.json", signSHA1)
+			Ω(res.Body.String()).Should(ContainSubstring(`"duplicate":true`))
+			Ω(received).Should(HaveLen(1))
+		})
+	})
+
+	Describe("Store deliveries", func() {
+		var dir string
+		var store *infrastructure.DeliveryStore
+		var router *mux.Router
+		failures := 0
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "deliveries")
+			Ω(err).ShouldNot(HaveOccurred())
+			store, err = infrastructure.NewDeliveryStore(dir)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			failures = 0
+			dispatcher := usecases.NewWebhookDispatcher()
+			dispatcher.Store = store
+			dispatcher.Handle("push", func(event domain.WebhookEvent) error {
+				if failures > 0 {
+					failures--
+					return errors.New("consumer is down")
+				}
+				received = append(received, event)
+				return nil
+			})
+			handler.Webhooks = dispatcher
+
+			router = mux.NewRouter()
+			router.HandleFunc("/deliveries", handler.ShowDeliveries).Methods("GET")
+			router.HandleFunc("/deliveries/redeliver", handler.RedeliverRange).Methods("POST")
+			router.HandleFunc("/deliveries/{id}", handler.ShowDelivery).Methods("GET")
+			router.HandleFunc("/deliveries/{id}/redeliver", handler.RedeliverDelivery).Methods("POST")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		admin := func(method, url string) *httptest.ResponseRecorder {
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, httptest.NewRequest(method, url, nil))
+			return res
+		}
+
+		It("Should store the delivery with its result", func() {
+			deliver("push", "d-1", "push.json", signSHA1)
+
+			delivery, err := store.GetDelivery("d-1")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(delivery.Event).Should(Equal("push"))
+			Ω(delivery.Result).Should(Equal(domain.DeliveryProcessed))
+			Ω(delivery.Attempts).Should(Equal(1))
+			Ω(delivery.Headers).Should(HaveKey("X-Hub-Signature"))
+			Ω(delivery.ProcessedAt).ShouldNot(BeNil())
+
+			payload, _ := ioutil.ReadFile("testdata/webhooks/push.json")
+			Ω(delivery.Payload).Should(MatchJSON(payload))
+		})
+
+		It("Should store a delivery that cannot be parsed", func() {
+			res := deliver("push", "d-1", "invalid.json", signSHA1)
+			Ω(res.Code).Should(Equal(http.StatusBadRequest))
+
+			delivery, err := store.GetDelivery("d-1")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(delivery.Result).Should(Equal(domain.DeliveryInvalid))
+
+			res = admin("POST", "/deliveries/d-1/redeliver")
+			Ω(res.Code).Should(Equal(http.StatusInternalServerError))
+		})
+
+		It("Should reject a payload that does not match the event type", func() {
+			res := deliver("push", "d-1", "ping_array.json", signSHA1)
+			Ω(res.Code).Should(Equal(http.StatusBadRequest))
+			Ω(received).Should(BeEmpty())
+		})
+
+		It("Should list and inspect deliveries", func() {
+			deliver("push", "d-1", "push.json", signSHA1)
+			deliver("push", "d-2", "push.json", signSHA1)
+
+			res := admin("GET", "/deliveries")
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(res.Body.String()).Should(ContainSubstring(`"id":"d-1"`))
+			Ω(res.Body.String()).Should(ContainSubstring(`"id":"d-2"`))
+			Ω(res.Body.String()).ShouldNot(ContainSubstring(`"payload"`))
+
+			res = admin("GET", "/deliveries/d-2")
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(res.Body.String()).Should(ContainSubstring(`"payload"`))
+
+			res = admin("GET", "/deliveries/d-3")
+			Ω(res.Code).Should(Equal(http.StatusNotFound))
+		})
+
+		It("Should redeliver a failed delivery", func() {
+			failures = 1
+			deliver("push", "d-1", "push.json", signSHA1)
+
+			delivery, _ := store.GetDelivery("d-1")
+			Ω(delivery.Result).Should(Equal(domain.DeliveryFailed))
+			Ω(delivery.Error).Should(Equal("consumer is down"))
+
+			res := admin("POST", "/deliveries/d-1/redeliver")
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(res.Body.String()).Should(ContainSubstring(`"result":"processed"`))
+			Ω(res.Body.String()).Should(ContainSubstring(`"attempts":2`))
+			Ω(received).Should(HaveLen(1))
+		})
+
+		It("Should redeliver the deliveries received in a time range", func() {
+			deliver("push", "d-1", "push.json", signSHA1)
+			deliver("push", "d-2", "push.json", signSHA1)
+			Ω(received).Should(HaveLen(2))
+
+			res := admin("POST", "/deliveries/redeliver")
+			Ω(res.Code).Should(Equal(http.StatusBadRequest))
+
+			res = admin("POST", "/deliveries/redeliver?from=2000-01-01T00:00:00Z")
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(received).Should(HaveLen(4))
+
+			res = admin("POST", "/deliveries/redeliver?from=2000-01-01T00:00:00Z&to=2000-01-02T00:00:00Z")
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(res.Body.String()).Should(ContainSubstring(`"deliveries":[]`))
+			Ω(received).Should(HaveLen(4))
+		})
+
+		It("Should skip the deliveries in a range that cannot be redelivered", func() {
+			deliver("push", "d-1", "invalid.json", signSHA1)
+			deliver("push", "d-2", "push.json", signSHA1)
+			Ω(received).Should(HaveLen(1))
+
+			res := admin("POST", "/deliveries/redeliver?from=2000-01-01T00:00:00Z")
+			Ω(res.Code).Should(Equal(http.StatusOK))
+			Ω(received).Should(HaveLen(2))
+
+			var body struct {
+				Deliveries []domain.Delivery `json:"deliveries"`
+			}
+			Ω(json.Unmarshal(res.Body.Bytes(), &body)).Should(Succeed())
+			Ω(body.Deliveries).Should(HaveLen(2))
+			Ω(body.Deliveries[0].ID).Should(Equal("d-1"))
+			Ω(body.Deliveries[0].Result).Should(Equal(domain.DeliveryInvalid))
+			Ω(body.Deliveries[0].Error).Should(HavePrefix("invalid payload"))
+			Ω(body.Deliveries[1].ID).Should(Equal("d-2"))
+			Ω(body.Deliveries[1].Result).Should(Equal(domain.DeliveryProcessed))
+		})
+	})
+})
@@ -2,11 +2,13 @@ package interfaces
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/Tinker-Ware/gh-service/domain"
 	"github.com/gorilla/mux"
@@ -38,12 +40,22 @@ type multipleFilesRequest struct {
 	Files  []domain.File `json:"files"`
 }
 
+// repositoryResponse and repositoriesResponse hold repositories or, when the
+// request selects fields, the maps returned by selectFields
 type repositoryResponse struct {
-	Repository *domain.Repository `json:"repository"`
+	Repository interface{} `json:"repository"`
 }
 
 type repositoriesResponse struct {
-	Repositories []domain.Repository `json:"repositories"`
+	Repositories interface{} `json:"repositories"`
+}
+
+type transferWrapper struct {
+	Transfer transferRequest `json:"transfer"`
+}
+
+type forkWrapper struct {
+	Fork forkRequest `json:"fork"`
 }
 
 // GHInteractor defines all the functions the Github Interactor should have
@@ -51,27 +63,101 @@ type GHInteractor interface {
 	GHCallback(code, state, incomingState string) (*domain.User, error)
 	GHLogin() (string, string)
 	ShowUser(username string) (*domain.User, error)
-	ShowRepos(username string) ([]domain.Repository, error)
+	ShowRepos(username string, opt domain.RepositoryListOptions) (*domain.RepositoryPage, error)
+	StreamRepos(ctx context.Context, username string, opt domain.RepositoryListOptions, emit func([]domain.Repository) error) error
+	SearchRepos(opt domain.SearchOptions) (*domain.RepositorySearch, error)
+	SearchCode(opt domain.SearchOptions) (*domain.CodeSearch, error)
 	CreateRepo(username, reponame, org string, private bool) (*domain.Repository, error)
 	ShowRepo(username, repo string) (*domain.Repository, error)
 	ShowKeys(username string) ([]domain.Key, error)
 	CreateKey(username string, key *domain.Key) error
 	ShowKey(username string, id int) (*domain.Key, error)
+	DeleteKey(username string, id int) error
+	ShowGPGKeys(username string) ([]domain.GPGKey, error)
+	CreateGPGKey(username, armoredPublicKey string) (*domain.GPGKey, error)
+	DeleteGPGKey(username string, id int) error
 	CreateFile(file domain.File, author domain.Author, username, repo string) error
 	AddFiles(files []domain.File, author domain.Author, username, repo string) error
 	AddDeployKey(username, reponame string, key *domain.Key) error
+	ShowDeployKeys(username, reponame string) ([]domain.Key, error)
+	ShowCollaborators(owner, repo string) ([]domain.Collaborator, error)
+	AddCollaborator(owner, repo, user, permission string) (*domain.Invitation, error)
+	RemoveCollaborator(owner, repo, user string) error
+	ShowInvitations(owner, repo string) ([]domain.Invitation, error)
+	CancelInvitation(owner, repo string, id int) error
+	ShowOrganizations() ([]domain.Organization, error)
+	ShowOrgRepos(org string) ([]domain.Repository, error)
+	ShowTeams(org string) ([]domain.Team, error)
+	GrantTeamAccess(team int, owner, repo, permission string) error
+	ShowDeployKey(username, reponame string, id int) (*domain.Key, error)
+	DeleteDeployKey(username, reponame string, id int) error
+	GenerateDeployKey(username, reponame string, request domain.KeyRequest) (*domain.GeneratedKey, error)
+	StartKeyRotation(owner, repo string, request domain.RotationRequest) (*domain.KeyRotation, *domain.GeneratedKey, error)
+	ResumeKeyRotation(owner, repo, id string, request domain.KeyRequest) (*domain.KeyRotation, *domain.GeneratedKey, error)
+	ConfirmKeyRotation(owner, repo, id string) (*domain.KeyRotation, error)
+	ShowKeyRotations(owner, repo string) ([]domain.KeyRotation, error)
+	ShowExpiredRotations() ([]domain.KeyRotation, error)
+	CompleteExpiredRotations(owner, repo string) ([]domain.KeyRotation, error)
+	ArchiveRepo(username, repo string) (*domain.Repository, error)
+	UnarchiveRepo(username, repo string) (*domain.Repository, error)
+	ShowTopics(owner, repo string) ([]string, error)
+	ReplaceTopics(owner, repo string, topics []string) ([]string, error)
+	DetectStacks(owner, repo, ref string) (*domain.StackReport, error)
+	CheckReadiness(owner, repo, ref string) (*domain.ReadinessReport, error)
+	ShowSecrets(owner, repo, environment string) ([]domain.Secret, error)
+	SetSecret(owner, repo, environment, name string, value []byte) (bool, error)
+	DeleteSecret(owner, repo, environment, name string) error
+	TransferRepo(ctx context.Context, username, repo, newOwner string, teamIDs []int) (*domain.Repository, error)
+	ForkRepo(ctx context.Context, owner, repo, org, name string) (*domain.Repository, error)
+	ShowBranches(username, repo string) ([]domain.Branch, error)
+	CreateBranch(username, repo, branch, from string) (*domain.Branch, error)
+	DeleteBranch(username, repo, branch string) error
+	SetDefaultBranch(username, repo, branch string) (*domain.Repository, error)
+	ShowBranchProtection(username, repo, branch string) (*domain.BranchProtection, error)
+	ApplyBranchProtection(username, repo, branch string, policy *domain.BranchProtection) (*domain.BranchProtection, error)
+	CreateTag(username, repo string, tag *domain.Tag) error
+	ShowReleases(username, repo string) ([]domain.Release, error)
+	CreateRelease(username, repo string, release *domain.Release) (*domain.Release, error)
+	UpdateRelease(username, repo string, id int, release *domain.Release) (*domain.Release, error)
+	UploadReleaseAsset(username, repo string, id int, name, label, contentType string, size int64, content io.Reader) (*domain.ReleaseAsset, error)
+	CreateDeployment(username, repo string, deployment *domain.Deployment) (*domain.Deployment, error)
+	ShowDeployments(username, repo, environment string) ([]domain.Deployment, error)
+	UpdateDeploymentStatus(username, repo string, id int, status *domain.DeploymentStatus) (*domain.DeploymentStatus, error)
+	CreateStatus(username, repo, sha string, status *domain.CommitStatus) (*domain.CommitStatus, error)
+	ShowCombinedStatus(username, repo, ref string) (*domain.CombinedStatus, error)
+	ShowHooks(owner, repo string) ([]domain.Hook, error)
+	CreateHook(owner, repo string, hook *domain.Hook) (*domain.Hook, bool, error)
+	UpdateHook(owner, repo string, id int, hook *domain.Hook) (*domain.Hook, error)
+	DeleteHook(owner, repo string, id int) error
+	PingHook(owner, repo string, id int) error
+	ShowHookSecret(id int) (string, error)
 }
 
-// WebServiceHandler has all the necessary fields to run a web-based interface
+// WebServiceHandler has all the necessary fields to run a web-based interface.
+// Interactor builds the interactor of a request from the github repository
+// authenticated by GetToken, GHInteractor is used when it is nil or the
+// request has no repository.
 type WebServiceHandler struct {
-	GHInteractor GHInteractor
-	APIHost      string
+	GHInteractor   GHInteractor
+	Interactor     func(repo *GithubRepository) GHInteractor
+	APIHost        string
+	WebhookSecrets []string
+	Webhooks       WebhookDispatcher
+}
+
+// interactor returns the interactor for the github token of the request
+func (handler WebServiceHandler) interactor(req *http.Request) GHInteractor {
+	repo, ok := requestRepository(req)
+	if !ok || handler.Interactor == nil {
+		return handler.GHInteractor
+	}
+	return handler.Interactor(repo)
 }
 
 // Login is a helper method to test the Github oauth login
 func (handler WebServiceHandler) Login(res http.ResponseWriter, req *http.Request) {
 
-	url, state := handler.GHInteractor.GHLogin()
+	url, state := handler.interactor(req).GHLogin()
 
 	fmt.Println("State login " + state)
 
@@ -137,7 +223,7 @@ func (handler WebServiceHandler) Callback(res http.ResponseWriter, req *http.Req
 		return
 	}
 
-	token, err := handler.GHInteractor.GHCallback(oauthwrapper.OauthRequest.Code, "", oauthwrapper.OauthRequest.State)
+	token, err := handler.interactor(req).GHCallback(oauthwrapper.OauthRequest.Code, "", oauthwrapper.OauthRequest.State)
 	if err != nil {
 		res.WriteHeader(http.StatusInternalServerError)
 		errS := fmt.Sprintf("Github oauth error: %s", err.Error())
@@ -216,7 +302,7 @@ func (handler WebServiceHandler) ShowUser(res http.ResponseWriter, req *http.Req
 	vars := mux.Vars(req)
 	username := vars["username"]
 
-	user, err := handler.GHInteractor.ShowUser(username)
+	user, err := handler.interactor(req).ShowUser(username)
 
 	if err != nil {
 		fmt.Println(err.Error())
@@ -231,40 +317,6 @@ func (handler WebServiceHandler) ShowUser(res http.ResponseWriter, req *http.Req
 	res.Write([]byte(userB))
 }
 
-// ShowRepos returns a JSON with all the repositories within an user account
-func (handler WebServiceHandler) ShowRepos(res http.ResponseWriter, req *http.Request) {
-	vars := mux.Vars(req)
-	username := vars["username"]
-
-	repos, err := handler.GHInteractor.ShowRepos(username)
-
-	if err != nil {
-		res.WriteHeader(http.StatusInternalServerError)
-		errS := fmt.Sprintf("Cannot retrieve repositories: %s", err.Error())
-
-		log.Println(errS)
-
-		resErr := httpError{
-			Error: errS,
-		}
-
-		respBytes, _ := json.Marshal(resErr)
-		res.Header().Set("Content-Type", "application/json")
-		res.Write(respBytes)
-		return
-	}
-
-	reposR := repositoriesResponse{
-		Repositories: repos,
-	}
-
-	reposB, _ := json.Marshal(reposR)
-
-	res.Header().Set("Content-Type", "application/json; charset=utf-8")
-	res.WriteHeader(http.StatusOK)
-	res.Write([]byte(reposB))
-}
-
 // CreateRepo creates a repository in the user account and returns a JSON response
 func (handler WebServiceHandler) CreateRepo(res http.ResponseWriter, req *http.Request) {
 	defer req.Body.Close()
@@ -278,7 +330,7 @@ func (handler WebServiceHandler) CreateRepo(res http.ResponseWriter, req *http.R
 		return
 	}
 
-	r, err := handler.GHInteractor.CreateRepo(repo.Owner, repo.Name, repo.Org, repo.Private)
+	r, err := handler.interactor(req).CreateRepo(repo.Owner, repo.Name, repo.Org, repo.Private)
 	if err != nil {
 		fmt.Println(err.Error())
 		res.WriteHeader(http.StatusInternalServerError)
@@ -293,109 +345,136 @@ func (handler WebServiceHandler) CreateRepo(res http.ResponseWriter, req *http.R
 
 }
 
-// ShowRepo returns a JSON response of a single repository
+// ShowRepo returns a JSON response of a single repository, the fields query
+// parameter selects the fields of the repository in the response
 func (handler WebServiceHandler) ShowRepo(res http.ResponseWriter, req *http.Request) {
 	vars := mux.Vars(req)
 	username := vars["username"]
 	repoName := vars["repo"]
 
-	repo, err := handler.GHInteractor.ShowRepo(username, repoName)
+	fields, err := fieldsQuery(req.URL.Query())
 	if err != nil {
-		fmt.Println(err.Error())
-		res.WriteHeader(http.StatusInternalServerError)
+		writeError(res, http.StatusBadRequest, err.Error())
 		return
-
 	}
 
-	repoR := repositoryResponse{
-		Repository: repo,
+	repo, err := handler.interactor(req).ShowRepo(username, repoName)
+	if err == domain.ErrRepoNotFound {
+		writeError(res, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve repository: %s", err.Error()))
+		return
 	}
 
-	repoB, _ := json.Marshal(repoR)
-
-	res.Header().Set("Content-Type", "application/json; charset=utf-8")
-	res.WriteHeader(http.StatusOK)
-	res.Write([]byte(repoB))
-
+	writeJSON(res, http.StatusOK, repositoryResponse{Repository: selectFields(*repo, fields)})
 }
 
-// ShowKeys returns all the keys within github in a JSON response
-func (handler WebServiceHandler) ShowKeys(res http.ResponseWriter, req *http.Request) {
+// ArchiveRepo marks a repository as archived and returns it as a JSON response
+func (handler WebServiceHandler) ArchiveRepo(res http.ResponseWriter, req *http.Request) {
 	vars := mux.Vars(req)
 	username := vars["username"]
+	repoName := vars["repo"]
 
-	keys, err := handler.GHInteractor.ShowKeys(username)
+	repo, err := handler.interactor(req).ArchiveRepo(username, repoName)
 	if err != nil {
-		fmt.Println(err.Error())
-		res.WriteHeader(http.StatusInternalServerError)
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot archive repository: %s", err.Error()))
 		return
-
 	}
 
-	keysB, _ := json.Marshal(keys)
-
-	res.Header().Set("Content-Type", "application/json; charset=utf-8")
-	res.WriteHeader(http.StatusOK)
-	res.Write([]byte(keysB))
-
+	writeJSON(res, http.StatusOK, repositoryResponse{Repository: repo})
 }
 
-// CreateKey creates a key in the user repository
-func (handler WebServiceHandler) CreateKey(res http.ResponseWriter, req *http.Request) {
-	defer req.Body.Close()
+// UnarchiveRepo makes an archived repository writable again
+func (handler WebServiceHandler) UnarchiveRepo(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
 
-	decoder := json.NewDecoder(req.Body)
-	key := domain.Key{}
-	err := decoder.Decode(&key)
+	repo, err := handler.interactor(req).UnarchiveRepo(username, repoName)
 	if err != nil {
-		res.WriteHeader(http.StatusInternalServerError)
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot unarchive repository: %s", err.Error()))
 		return
 	}
 
+	writeJSON(res, http.StatusOK, repositoryResponse{Repository: repo})
+}
+
+// TransferRepo transfers a repository to a new owner and returns it from its new
+// location, or 202 with the location when github is still moving it
+func (handler WebServiceHandler) TransferRepo(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
 	vars := mux.Vars(req)
 	username := vars["username"]
+	repoName := vars["repo"]
 
-	err = handler.GHInteractor.CreateKey(username, &key)
+	decoder := json.NewDecoder(req.Body)
+	var transfer transferWrapper
+	err := decoder.Decode(&transfer)
+	if err != nil || transfer.Transfer.NewOwner == "" {
+		writeError(res, 422, "cannot process request")
+		return
+	}
 
+	newOwner := transfer.Transfer.NewOwner
+	repo, err := handler.interactor(req).TransferRepo(req.Context(), username, repoName, newOwner, transfer.Transfer.TeamIDs)
+	if err == domain.ErrTransferPending {
+		// Github finishes the transfer later, point the client to where the
+		// repository will be
+		location := strings.TrimSuffix(req.URL.Path, fmt.Sprintf("/%s/%s/transfer", username, repoName))
+		res.Header().Set("Location", fmt.Sprintf("%s/%s/%s", location, newOwner, repoName))
+		fullName := newOwner + "/" + repoName
+		writeJSON(res, http.StatusAccepted, repositoryResponse{Repository: domain.Repository{
+			Owner:    &newOwner,
+			Name:     &repoName,
+			FullName: &fullName,
+		}})
+		return
+	}
 	if err != nil {
-		fmt.Println(err.Error())
-		res.WriteHeader(http.StatusInternalServerError)
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot transfer repository: %s", err.Error()))
 		return
 	}
 
-	keyB, _ := json.Marshal(key)
-
-	res.Header().Set("Content-Type", "application/json; charset=utf-8")
-	res.WriteHeader(http.StatusOK)
-	res.Write([]byte(keyB))
+	writeJSON(res, http.StatusOK, repositoryResponse{Repository: repo})
 }
 
-// ShowKey shows a single key whithin the user account on github
-func (handler WebServiceHandler) ShowKey(res http.ResponseWriter, req *http.Request) {
+// ForkRepo forks a repository into the user account or an organization and
+// returns the fork once it is ready to use, or 202 with its location when
+// github is still copying it
+func (handler WebServiceHandler) ForkRepo(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
 	vars := mux.Vars(req)
-	username := vars["username"]
-	idStr := vars["id"]
+	owner := vars["username"]
+	repoName := vars["repo"]
 
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		fmt.Println(err.Error())
-		res.WriteHeader(http.StatusInternalServerError)
-		return
+	var fork forkWrapper
+	if req.ContentLength != 0 {
+		decoder := json.NewDecoder(req.Body)
+		err := decoder.Decode(&fork)
+		if err != nil {
+			writeError(res, 422, "cannot process request")
+			return
+		}
 	}
 
-	key, err := handler.GHInteractor.ShowKey(username, id)
+	repo, err := handler.interactor(req).ForkRepo(req.Context(), owner, repoName, fork.Fork.Organization, fork.Fork.Name)
+	if err == domain.ErrForkPending {
+		// Github keeps copying the repository, point the client to the fork
+		location := strings.TrimSuffix(req.URL.Path, fmt.Sprintf("/%s/%s/fork", owner, repoName))
+		res.Header().Set("Location", fmt.Sprintf("%s/%s", location, *repo.FullName))
+		writeJSON(res, http.StatusAccepted, repositoryResponse{Repository: repo})
+		return
+	}
 	if err != nil {
-		fmt.Println(err.Error())
-		res.WriteHeader(http.StatusInternalServerError)
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot fork repository: %s", err.Error()))
 		return
 	}
 
-	keyB, _ := json.Marshal(key)
-
-	res.Header().Set("Content-Type", "application/json; charset=utf-8")
-	res.WriteHeader(http.StatusOK)
-	res.Write([]byte(keyB))
-
+	writeJSON(res, http.StatusCreated, repositoryResponse{Repository: repo})
 }
 
 // AddFileToRepository adds a single file within an user repository
@@ -413,7 +492,7 @@ func (handler WebServiceHandler) AddFileToRepository(res http.ResponseWriter, re
 		return
 	}
 
-	err = handler.GHInteractor.CreateFile(file.File, file.Author, username, repoName)
+	err = handler.interactor(req).CreateFile(file.File, file.Author, username, repoName)
 	if err != nil {
 		fmt.Println(err.Error())
 		res.WriteHeader(http.StatusInternalServerError)
@@ -441,7 +520,7 @@ func (handler WebServiceHandler) AddMultipleFilesToRepository(res http.ResponseW
 		res.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	err = handler.GHInteractor.AddFiles(request.Files, request.Author, username, repoName)
+	err = handler.interactor(req).AddFiles(request.Files, request.Author, username, repoName)
 	if err != nil {
 		res.WriteHeader(http.StatusInternalServerError)
 		return
@@ -451,42 +530,27 @@ func (handler WebServiceHandler) AddMultipleFilesToRepository(res http.ResponseW
 
 }
 
-type keyWrapper struct {
-	Key domain.Key `json:"deploy_key"`
-}
-
-func (handler WebServiceHandler) CreateRepoDeployKey(res http.ResponseWriter, req *http.Request) {
-	defer req.Body.Close()
-
-	vars := mux.Vars(req)
-
-	defer req.Body.Close()
-
-	username := vars["username"]
-	repoName := vars["repo"]
+// writeError logs an error message and sends it as a JSON response
+func writeError(res http.ResponseWriter, status int, errS string) {
+	log.Println(errS)
 
-	decoder := json.NewDecoder(req.Body)
-	var key keyWrapper
-	err := decoder.Decode(&key)
-	if err != nil {
-		log.Printf("Error unmarshaling json in CreateRepoDeployKey %s", err.Error())
-		res.WriteHeader(http.StatusInternalServerError)
-		return
+	resErr := httpError{
+		Error: errS,
 	}
 
-	err = handler.GHInteractor.AddDeployKey(username, repoName, &key.Key)
-	if err != nil {
-		log.Printf("Cannot create deploy key %s", err.Error())
-		res.WriteHeader(http.StatusInternalServerError)
-		return
-	}
+	respBytes, _ := json.Marshal(resErr)
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(status)
+	res.Write(respBytes)
+}
 
-	keyB, _ := json.Marshal(key)
+// writeJSON sends v as a JSON response with the given status
+func writeJSON(res http.ResponseWriter, status int, v interface{}) {
+	respBytes, _ := json.Marshal(v)
 
 	res.Header().Set("Content-Type", "application/json; charset=utf-8")
-	res.WriteHeader(http.StatusOK)
-	res.Write([]byte(keyB))
-
+	res.WriteHeader(status)
+	res.Write(respBytes)
 }
 
 func tokenToJSON(token *oauth2.Token) (string, error) {
@@ -0,0 +1,158 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type branchesResponse struct {
+	Branches []domain.Branch `json:"branches"`
+}
+
+type branchResponse struct {
+	Branch *domain.Branch `json:"branch"`
+}
+
+type branchRequest struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+}
+
+type branchWrapper struct {
+	Branch branchRequest `json:"branch"`
+}
+
+type branchProtectionWrapper struct {
+	BranchProtection *domain.BranchProtection `json:"branch_protection"`
+}
+
+type defaultBranchRequest struct {
+	DefaultBranch string `json:"default_branch"`
+}
+
+// ShowBranches returns the branches of a repository with their head SHA
+func (handler WebServiceHandler) ShowBranches(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	branches, err := handler.interactor(req).ShowBranches(username, repoName)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve branches: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, branchesResponse{Branches: branches})
+}
+
+// CreateBranch creates a branch from a ref or a commit SHA
+func (handler WebServiceHandler) CreateBranch(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var branch branchWrapper
+	err := decoder.Decode(&branch)
+	if err != nil || branch.Branch.Name == "" || branch.Branch.From == "" {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	b, err := handler.interactor(req).CreateBranch(username, repoName, branch.Branch.Name, branch.Branch.From)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot create branch: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, branchResponse{Branch: b})
+}
+
+// DeleteBranch removes a branch from a repository
+func (handler WebServiceHandler) DeleteBranch(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+	branch := vars["branch"]
+
+	err := handler.interactor(req).DeleteBranch(username, repoName, branch)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot delete branch: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// SetDefaultBranch changes the default branch of a repository
+func (handler WebServiceHandler) SetDefaultBranch(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var branch defaultBranchRequest
+	err := decoder.Decode(&branch)
+	if err != nil || branch.DefaultBranch == "" {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	repo, err := handler.interactor(req).SetDefaultBranch(username, repoName, branch.DefaultBranch)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot change default branch: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, repositoryResponse{Repository: repo})
+}
+
+// ShowBranchProtection returns the protection policy of a branch
+func (handler WebServiceHandler) ShowBranchProtection(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+	branch := vars["branch"]
+
+	policy, err := handler.interactor(req).ShowBranchProtection(username, repoName, branch)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve branch protection: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, branchProtectionWrapper{BranchProtection: policy})
+}
+
+// ApplyBranchProtection makes the protection of a branch match the policy in the request
+func (handler WebServiceHandler) ApplyBranchProtection(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+	branch := vars["branch"]
+
+	decoder := json.NewDecoder(req.Body)
+	var policy branchProtectionWrapper
+	err := decoder.Decode(&policy)
+	if err != nil || policy.BranchProtection == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	p, err := handler.interactor(req).ApplyBranchProtection(username, repoName, branch, policy.BranchProtection)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot apply branch protection: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, branchProtectionWrapper{BranchProtection: p})
+}
@@ -0,0 +1,137 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type collaboratorWrapper struct {
+	Collaborator *domain.Collaborator `json:"collaborator"`
+}
+
+type collaboratorsResponse struct {
+	Collaborators []domain.Collaborator `json:"collaborators"`
+}
+
+type invitationWrapper struct {
+	Invitation *domain.Invitation `json:"invitation"`
+}
+
+type invitationsResponse struct {
+	Invitations []domain.Invitation `json:"invitations"`
+}
+
+// ShowCollaborators returns the collaborators of a repository with their permission
+func (handler WebServiceHandler) ShowCollaborators(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	collaborators, err := handler.interactor(req).ShowCollaborators(owner, repoName)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve collaborators: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, collaboratorsResponse{Collaborators: collaborators})
+}
+
+// AddCollaborator grants the user in the route the permission of the request.
+// The response is 201 with the invitation when github invites the user, and 204
+// when an existing collaborator got the permission.
+func (handler WebServiceHandler) AddCollaborator(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+	user := vars["user"]
+
+	decoder := json.NewDecoder(req.Body)
+	var collaborator collaboratorWrapper
+	err := decoder.Decode(&collaborator)
+	if err != nil && err != io.EOF {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	permission := ""
+	if collaborator.Collaborator != nil && collaborator.Collaborator.Permission != nil {
+		permission = *collaborator.Collaborator.Permission
+	}
+
+	invitation, err := handler.interactor(req).AddCollaborator(owner, repoName, user, permission)
+	if err == domain.ErrInvalidPermission {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot add collaborator: %s", err.Error()))
+		return
+	}
+
+	if invitation == nil {
+		res.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, invitationWrapper{Invitation: invitation})
+}
+
+// RemoveCollaborator removes the access of a user to a repository
+func (handler WebServiceHandler) RemoveCollaborator(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+	user := vars["user"]
+
+	err := handler.interactor(req).RemoveCollaborator(owner, repoName, user)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot remove collaborator: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// ShowInvitations returns the pending invitations of a repository
+func (handler WebServiceHandler) ShowInvitations(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	invitations, err := handler.interactor(req).ShowInvitations(owner, repoName)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve invitations: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, invitationsResponse{Invitations: invitations})
+}
+
+// CancelInvitation deletes a pending invitation
+func (handler WebServiceHandler) CancelInvitation(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid invitation id: %s", vars["id"]))
+		return
+	}
+
+	err = handler.interactor(req).CancelInvitation(owner, repoName, id)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot cancel invitation: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
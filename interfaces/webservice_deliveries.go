@@ -0,0 +1,170 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type deliveryWrapper struct {
+	Delivery *domain.Delivery `json:"delivery"`
+}
+
+type deliveriesWrapper struct {
+	Deliveries []domain.Delivery `json:"deliveries"`
+}
+
+// ShowDeliveries lists the stored webhook deliveries, the from and to query
+// parameters are RFC3339 times that limit when they were received
+func (handler WebServiceHandler) ShowDeliveries(res http.ResponseWriter, req *http.Request) {
+	from, to, err := deliveryRange(req)
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	deliveries, err := handler.Webhooks.ShowDeliveries(from, to)
+	if err != nil {
+		writeDeliveryError(res, "Cannot retrieve deliveries", err)
+		return
+	}
+
+	writeJSON(res, http.StatusOK, deliveriesWrapper{Deliveries: summarize(deliveries)})
+}
+
+// ShowDelivery returns a stored delivery with its headers and payload
+func (handler WebServiceHandler) ShowDelivery(res http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+
+	delivery, err := handler.Webhooks.ShowDelivery(id)
+	if err != nil {
+		writeDeliveryError(res, "Cannot retrieve delivery", err)
+		return
+	}
+
+	writeJSON(res, http.StatusOK, deliveryWrapper{Delivery: delivery})
+}
+
+// RedeliverDelivery dispatches a stored delivery to the handlers again, the
+// result of the handlers is in the returned delivery
+func (handler WebServiceHandler) RedeliverDelivery(res http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+
+	delivery, err := handler.redeliver(id)
+	if delivery == nil {
+		writeDeliveryError(res, "Cannot redeliver delivery", err)
+		return
+	}
+
+	writeJSON(res, http.StatusOK, deliveryWrapper{Delivery: delivery})
+}
+
+// RedeliverRange dispatches again every delivery received between the from and
+// to query parameters, from is required so the whole store is not replayed by
+// mistake. Deliveries are replayed in the order they were received, one that
+// cannot be replayed is returned with the reason in its error and the rest of
+// the range is still replayed.
+func (handler WebServiceHandler) RedeliverRange(res http.ResponseWriter, req *http.Request) {
+	from, to, err := deliveryRange(req)
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	if from.IsZero() {
+		writeError(res, http.StatusBadRequest, "from is required")
+		return
+	}
+
+	deliveries, err := handler.Webhooks.ShowDeliveries(from, to)
+	if err != nil {
+		writeDeliveryError(res, "Cannot retrieve deliveries", err)
+		return
+	}
+
+	redelivered := []domain.Delivery{}
+	for _, d := range deliveries {
+		delivery, err := handler.redeliver(d.ID)
+		if delivery == nil {
+			d.Error = err.Error()
+			redelivered = append(redelivered, d)
+			continue
+		}
+		redelivered = append(redelivered, *delivery)
+	}
+
+	writeJSON(res, http.StatusOK, deliveriesWrapper{Deliveries: summarize(redelivered)})
+}
+
+// redeliver parses the stored payload and dispatches it, a failure in the
+// handlers is recorded in the returned delivery
+func (handler WebServiceHandler) redeliver(id string) (*domain.Delivery, error) {
+	delivery, err := handler.Webhooks.ShowDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.Result == domain.DeliveryInvalid {
+		return nil, fmt.Errorf("invalid payload: %s", delivery.Error)
+	}
+
+	event, err := parseEvent(delivery.ID, delivery.Event, delivery.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatchErr := handler.Webhooks.Redispatch(event)
+
+	delivery, err = handler.Webhooks.ShowDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, dispatchErr
+}
+
+func deliveryRange(req *http.Request) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	query := req.URL.Query()
+	if f := query.Get("from"); f != "" {
+		from, err = time.Parse(time.RFC3339, f)
+		if err != nil {
+			return from, to, fmt.Errorf("Invalid from: %s", err.Error())
+		}
+	}
+	if t := query.Get("to"); t != "" {
+		to, err = time.Parse(time.RFC3339, t)
+		if err != nil {
+			return from, to, fmt.Errorf("Invalid to: %s", err.Error())
+		}
+	}
+
+	return from, to, nil
+}
+
+// summarize drops the headers and payloads from a list of deliveries
+func summarize(deliveries []domain.Delivery) []domain.Delivery {
+	summaries := []domain.Delivery{}
+	for _, delivery := range deliveries {
+		delivery.Headers = nil
+		delivery.Payload = nil
+		summaries = append(summaries, delivery)
+	}
+
+	return summaries
+}
+
+func writeDeliveryError(res http.ResponseWriter, msg string, err error) {
+	switch err {
+	case domain.ErrDeliveryNotFound:
+		writeError(res, http.StatusNotFound, fmt.Sprintf("%s: %s", msg, err.Error()))
+	case domain.ErrNoDeliveryStore:
+		writeError(res, http.StatusNotImplemented, fmt.Sprintf("%s: %s", msg, err.Error()))
+	default:
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("%s: %s", msg, err.Error()))
+	}
+}
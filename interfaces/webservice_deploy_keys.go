@@ -0,0 +1,150 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type keyWrapper struct {
+	Key domain.Key `json:"deploy_key"`
+}
+
+type deployKeysResponse struct {
+	Keys []domain.Key `json:"deploy_keys"`
+}
+
+type keyRequestWrapper struct {
+	Key *domain.KeyRequest `json:"deploy_key"`
+}
+
+// CreateRepoDeployKey registers a deploy key in a repository, it is read only
+// unless read_only is false. The response has the ID of the created key.
+func (handler WebServiceHandler) CreateRepoDeployKey(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var key keyWrapper
+	err := decoder.Decode(&key)
+	if err != nil || key.Key.Key == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	err = handler.interactor(req).AddDeployKey(username, repoName, &key.Key)
+	if isKeyError(err) {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err == domain.ErrKeyAccessConflict {
+		writeError(res, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot create deploy key: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, key)
+}
+
+// ShowRepoDeployKeys returns the deploy keys of a repository
+func (handler WebServiceHandler) ShowRepoDeployKeys(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	keys, err := handler.interactor(req).ShowDeployKeys(username, repoName)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve deploy keys: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, deployKeysResponse{Keys: keys})
+}
+
+// ShowRepoDeployKey returns a single deploy key of a repository
+func (handler WebServiceHandler) ShowRepoDeployKey(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid deploy key id: %s", vars["id"]))
+		return
+	}
+
+	key, err := handler.interactor(req).ShowDeployKey(username, repoName, id)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve deploy key: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, keyWrapper{Key: *key})
+}
+
+// DeleteRepoDeployKey removes a deploy key from a repository
+func (handler WebServiceHandler) DeleteRepoDeployKey(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid deploy key id: %s", vars["id"]))
+		return
+	}
+
+	err = handler.interactor(req).DeleteDeployKey(username, repoName, id)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot delete deploy key: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// GenerateRepoDeployKey generates a key pair and registers it as a deploy key,
+// the private key is only sent in this response
+func (handler WebServiceHandler) GenerateRepoDeployKey(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var request keyRequestWrapper
+	err := decoder.Decode(&request)
+	if err != nil || request.Key == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	key, err := handler.interactor(req).GenerateDeployKey(username, repoName, *request.Key)
+	if err == domain.ErrInvalidKeyType || err == domain.ErrInvalidRecipient || err == domain.ErrKeyEncryption {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot generate deploy key: %s", err.Error()))
+		return
+	}
+
+	res.Header().Set("Cache-Control", "no-store")
+	writeJSON(res, http.StatusCreated, key)
+}
+
+// isKeyError reports whether a public key was rejected by the validation
+func isKeyError(err error) bool {
+	return err == domain.ErrInvalidKey || err == domain.ErrUnsupportedKey || err == domain.ErrWeakKey
+}
@@ -0,0 +1,101 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type deploymentWrapper struct {
+	Deployment *domain.Deployment `json:"deployment"`
+}
+
+type deploymentsResponse struct {
+	Deployments []domain.Deployment `json:"deployments"`
+}
+
+type deploymentStatusWrapper struct {
+	DeploymentStatus *domain.DeploymentStatus `json:"deployment_status"`
+}
+
+// ShowDeployments returns the deployments of a repository, the environment
+// query parameter filters them by environment
+func (handler WebServiceHandler) ShowDeployments(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	deployments, err := handler.interactor(req).ShowDeployments(username, repoName, req.URL.Query().Get("environment"))
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve deployments: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, deploymentsResponse{Deployments: deployments})
+}
+
+// CreateDeployment creates a deployment of a ref to an environment, the status
+// checks in required_contexts must pass on the ref and none are required when
+// it is left out
+func (handler WebServiceHandler) CreateDeployment(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var deployment deploymentWrapper
+	err := decoder.Decode(&deployment)
+	if err != nil || deployment.Deployment == nil || deployment.Deployment.Ref == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	d, err := handler.interactor(req).CreateDeployment(username, repoName, deployment.Deployment)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot create deployment: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, deploymentWrapper{Deployment: d})
+}
+
+// UpdateDeploymentStatus posts a new state for a deployment
+func (handler WebServiceHandler) UpdateDeploymentStatus(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid deployment id: %s", vars["id"]))
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var status deploymentStatusWrapper
+	err = decoder.Decode(&status)
+	if err != nil || status.DeploymentStatus == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	s, err := handler.interactor(req).UpdateDeploymentStatus(username, repoName, id, status.DeploymentStatus)
+	if err == domain.ErrInvalidDeploymentState {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot update deployment status: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, deploymentStatusWrapper{DeploymentStatus: s})
+}
@@ -0,0 +1,140 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+// The hook handlers manage organization hooks when the route has no repo
+
+type hookWrapper struct {
+	Hook *domain.Hook `json:"hook"`
+}
+
+type hooksResponse struct {
+	Hooks []domain.Hook `json:"hooks"`
+}
+
+// ShowHooks returns the webhooks of a repository or an organization
+func (handler WebServiceHandler) ShowHooks(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	hooks, err := handler.interactor(req).ShowHooks(owner, repoName)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve hooks: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, hooksResponse{Hooks: hooks})
+}
+
+// CreateHook registers a webhook, the response carries the hook secret and
+// is 200 instead of 201 when a hook with the same URL already existed
+func (handler WebServiceHandler) CreateHook(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var hook hookWrapper
+	err := decoder.Decode(&hook)
+	if err != nil || hook.Hook == nil || hook.Hook.URL == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	h, created, err := handler.interactor(req).CreateHook(owner, repoName, hook.Hook)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot create hook: %s", err.Error()))
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	writeJSON(res, status, hookWrapper{Hook: h})
+}
+
+// UpdateHook changes the events or the configuration of a webhook
+func (handler WebServiceHandler) UpdateHook(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid hook id: %s", vars["id"]))
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var hook hookWrapper
+	err = decoder.Decode(&hook)
+	if err != nil || hook.Hook == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	h, err := handler.interactor(req).UpdateHook(owner, repoName, id, hook.Hook)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot update hook: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, hookWrapper{Hook: h})
+}
+
+// DeleteHook removes a webhook
+func (handler WebServiceHandler) DeleteHook(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid hook id: %s", vars["id"]))
+		return
+	}
+
+	err = handler.interactor(req).DeleteHook(owner, repoName, id)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot delete hook: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// PingHook asks github to send a ping event to a webhook
+func (handler WebServiceHandler) PingHook(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid hook id: %s", vars["id"]))
+		return
+	}
+
+	err = handler.interactor(req).PingHook(owner, repoName, id)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot ping hook: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,196 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type userKeyWrapper struct {
+	Key *domain.Key `json:"key"`
+}
+
+type userKeysResponse struct {
+	Keys []domain.Key `json:"keys"`
+}
+
+type gpgKeyWrapper struct {
+	GPGKey *domain.GPGKey `json:"gpg_key"`
+}
+
+type gpgKeysResponse struct {
+	GPGKeys []domain.GPGKey `json:"gpg_keys"`
+}
+
+// ShowKeys returns the SSH keys of a github user
+func (handler WebServiceHandler) ShowKeys(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+
+	keys, err := handler.interactor(req).ShowKeys(username)
+	if err == domain.ErrNotKeyOwner {
+		writeError(res, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve keys: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, userKeysResponse{Keys: keys})
+}
+
+// CreateKey adds a SSH key to the user account, an identical key the account
+// already has is returned instead
+func (handler WebServiceHandler) CreateKey(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+
+	decoder := json.NewDecoder(req.Body)
+	var key userKeyWrapper
+	err := decoder.Decode(&key)
+	if err != nil || key.Key == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	err = handler.interactor(req).CreateKey(username, key.Key)
+	if err == domain.ErrNotKeyOwner {
+		writeError(res, http.StatusForbidden, err.Error())
+		return
+	}
+	if isKeyError(err) {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot create key: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, key)
+}
+
+// ShowKey returns a single SSH key of the user account
+func (handler WebServiceHandler) ShowKey(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid key id: %s", vars["id"]))
+		return
+	}
+
+	key, err := handler.interactor(req).ShowKey(username, id)
+	if err == domain.ErrNotKeyOwner {
+		writeError(res, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve key: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, userKeyWrapper{Key: key})
+}
+
+// DeleteKey removes a SSH key from the user account
+func (handler WebServiceHandler) DeleteKey(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid key id: %s", vars["id"]))
+		return
+	}
+
+	err = handler.interactor(req).DeleteKey(username, id)
+	if err == domain.ErrNotKeyOwner {
+		writeError(res, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot delete key: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// ShowGPGKeys returns the GPG keys of the user account
+func (handler WebServiceHandler) ShowGPGKeys(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+
+	keys, err := handler.interactor(req).ShowGPGKeys(username)
+	if err == domain.ErrNotKeyOwner {
+		writeError(res, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve GPG keys: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, gpgKeysResponse{GPGKeys: keys})
+}
+
+// CreateGPGKey adds the ASCII armored public_key of the request to the user account
+func (handler WebServiceHandler) CreateGPGKey(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+
+	decoder := json.NewDecoder(req.Body)
+	var key gpgKeyWrapper
+	err := decoder.Decode(&key)
+	if err != nil || key.GPGKey == nil || key.GPGKey.PublicKey == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	k, err := handler.interactor(req).CreateGPGKey(username, *key.GPGKey.PublicKey)
+	if err == domain.ErrNotKeyOwner {
+		writeError(res, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot create GPG key: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, gpgKeyWrapper{GPGKey: k})
+}
+
+// DeleteGPGKey removes a GPG key from the user account
+func (handler WebServiceHandler) DeleteGPGKey(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid GPG key id: %s", vars["id"]))
+		return
+	}
+
+	err = handler.interactor(req).DeleteGPGKey(username, id)
+	if err == domain.ErrNotKeyOwner {
+		writeError(res, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot delete GPG key: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,106 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type organizationsResponse struct {
+	Organizations []domain.Organization `json:"organizations"`
+}
+
+type teamsResponse struct {
+	Teams []domain.Team `json:"teams"`
+}
+
+type teamAccessWrapper struct {
+	Team struct {
+		Permission string `json:"permission"`
+	} `json:"team"`
+}
+
+// ShowOrganizations returns the organizations of the user with the role of the user in each of them
+func (handler WebServiceHandler) ShowOrganizations(res http.ResponseWriter, req *http.Request) {
+	orgs, err := handler.interactor(req).ShowOrganizations()
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve organizations: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, organizationsResponse{Organizations: orgs})
+}
+
+// ShowOrgRepos returns the repositories of an organization, the fields query
+// parameter selects the fields of every repository
+func (handler WebServiceHandler) ShowOrgRepos(res http.ResponseWriter, req *http.Request) {
+	org := mux.Vars(req)["org"]
+
+	fields, err := fieldsQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	repos, err := handler.interactor(req).ShowOrgRepos(org)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve repositories: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, repositoriesResponse{Repositories: selectRepositoryFields(repos, fields)})
+}
+
+// ShowTeams returns the teams of an organization
+func (handler WebServiceHandler) ShowTeams(res http.ResponseWriter, req *http.Request) {
+	org := mux.Vars(req)["org"]
+
+	teams, err := handler.interactor(req).ShowTeams(org)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve teams: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, teamsResponse{Teams: teams})
+}
+
+// GrantTeamAccess gives the team in the route the permission of the request
+// on a repository of its organization
+func (handler WebServiceHandler) GrantTeamAccess(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	team, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid team id: %s", vars["id"]))
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var access teamAccessWrapper
+	err = decoder.Decode(&access)
+	if err != nil && err != io.EOF {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	err = handler.interactor(req).GrantTeamAccess(team, owner, repoName, access.Team.Permission)
+	if err == domain.ErrInvalidPermission {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot grant team access: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type readinessReportWrapper struct {
+	Report *domain.ReadinessReport `json:"readiness_report"`
+}
+
+// ShowReadiness runs the readiness checks on a repository at the ref query
+// parameter, or at its default branch
+func (handler WebServiceHandler) ShowReadiness(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	report, err := handler.interactor(req).CheckReadiness(owner, repoName, req.URL.Query().Get("ref"))
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot check readiness: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, readinessReportWrapper{Report: report})
+}
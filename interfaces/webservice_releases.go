@@ -0,0 +1,164 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type tagWrapper struct {
+	Tag domain.Tag `json:"tag"`
+}
+
+type releaseWrapper struct {
+	Release *domain.Release `json:"release"`
+}
+
+type releasesResponse struct {
+	Releases []domain.Release `json:"releases"`
+}
+
+type assetResponse struct {
+	Asset *domain.ReleaseAsset `json:"asset"`
+}
+
+// CreateTag creates a lightweight or annotated tag in a repository, an annotated
+// tag without a message or a tagger is rejected with 422
+func (handler WebServiceHandler) CreateTag(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var tag tagWrapper
+	err := decoder.Decode(&tag)
+	if err != nil || tag.Tag.Name == "" || tag.Tag.Target == "" {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	err = handler.interactor(req).CreateTag(username, repoName, &tag.Tag)
+	if err == domain.ErrEmptyTagMessage || err == domain.ErrInvalidTagger {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot create tag: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, tag)
+}
+
+// ShowReleases returns all the releases of a repository
+func (handler WebServiceHandler) ShowReleases(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	releases, err := handler.interactor(req).ShowReleases(username, repoName)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve releases: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, releasesResponse{Releases: releases})
+}
+
+// CreateRelease creates a release, which can be a draft or a prerelease
+func (handler WebServiceHandler) CreateRelease(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var release releaseWrapper
+	err := decoder.Decode(&release)
+	if err != nil || release.Release == nil || release.Release.TagName == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	r, err := handler.interactor(req).CreateRelease(username, repoName, release.Release)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot create release: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, releaseWrapper{Release: r})
+}
+
+// UpdateRelease edits a release, only the fields present in the request are changed
+func (handler WebServiceHandler) UpdateRelease(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid release id: %s", vars["id"]))
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	var release releaseWrapper
+	err = decoder.Decode(&release)
+	if err != nil || release.Release == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	r, err := handler.interactor(req).UpdateRelease(username, repoName, id, release.Release)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot update release: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, releaseWrapper{Release: r})
+}
+
+// UploadReleaseAsset streams the request body to github as a release asset,
+// the asset name is taken from the name query parameter
+func (handler WebServiceHandler) UploadReleaseAsset(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeError(res, http.StatusBadRequest, fmt.Sprintf("Invalid release id: %s", vars["id"]))
+		return
+	}
+
+	name := req.URL.Query().Get("name")
+	if name == "" {
+		writeError(res, http.StatusBadRequest, "The asset name is required")
+		return
+	}
+
+	// Github needs the size of the asset before the upload starts
+	if req.ContentLength <= 0 {
+		writeError(res, http.StatusLengthRequired, "The asset size is required")
+		return
+	}
+
+	asset, err := handler.interactor(req).UploadReleaseAsset(username, repoName, id, name, req.URL.Query().Get("label"), req.Header.Get("Content-Type"), req.ContentLength, req.Body)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot upload asset: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, assetResponse{Asset: asset})
+}
@@ -0,0 +1,253 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// repositoryFields are the JSON names of the fields of domain.Repository
+var repositoryFields = jsonFields(reflect.TypeOf(domain.Repository{}))
+
+type repositoryPageResponse struct {
+	Repositories []interface{} `json:"repositories"`
+	Links        []domain.Link `json:"links,omitempty"`
+}
+
+// ShowRepos returns a page of the repositories of a user. The visibility,
+// affiliation, type, sort, direction, page and per_page query parameters are
+// passed to github, the pages around the returned one are in the Link header
+// and in the links of the response, fields selects the fields of every
+// repository. Requests that accept NDJSON get every page streamed instead.
+func (handler WebServiceHandler) ShowRepos(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+
+	opt, err := repositoryListOptions(req.URL.Query())
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields, err := fieldsQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if strings.Contains(req.Header.Get("Accept"), ndjsonContentType) {
+		handler.streamRepos(res, req, username, opt, fields)
+		return
+	}
+
+	page, err := handler.interactor(req).ShowRepos(username, opt)
+	if isListError(err) {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve repositories: %s", err.Error()))
+		return
+	}
+
+	links := pageLinks(req.URL, page.Pages)
+	setLinkHeader(res, links)
+
+	writeJSON(res, http.StatusOK, repositoryPageResponse{
+		Repositories: selectRepositoryFields(page.Repositories, fields),
+		Links:        links,
+	})
+}
+
+// streamRepos writes every repository as a line of JSON, flushing the response
+// after each page so clients get the repositories while the rest are fetched.
+// An error after the first page is written as a last line with an error field.
+func (handler WebServiceHandler) streamRepos(res http.ResponseWriter, req *http.Request, username string, opt domain.RepositoryListOptions, fields []string) {
+	flusher, _ := res.(http.Flusher)
+	encoder := json.NewEncoder(res)
+	started := false
+
+	err := handler.interactor(req).StreamRepos(req.Context(), username, opt, func(repos []domain.Repository) error {
+		if !started {
+			res.Header().Set("Content-Type", ndjsonContentType)
+			res.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		for _, repo := range repos {
+			err := encoder.Encode(selectFields(repo, fields))
+			if err != nil {
+				return err
+			}
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+
+	switch {
+	case err == nil:
+	case req.Context().Err() != nil:
+		log.Printf("Repository stream stopped: %s", req.Context().Err().Error())
+	case started:
+		errS := fmt.Sprintf("Cannot retrieve repositories: %s", err.Error())
+		log.Println(errS)
+		encoder.Encode(httpError{Error: errS})
+	case isListError(err):
+		writeError(res, http.StatusBadRequest, err.Error())
+	default:
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve repositories: %s", err.Error()))
+	}
+}
+
+func repositoryListOptions(query url.Values) (domain.RepositoryListOptions, error) {
+	opt := domain.RepositoryListOptions{
+		Visibility:  query.Get("visibility"),
+		Affiliation: query.Get("affiliation"),
+		Type:        query.Get("type"),
+		Sort:        query.Get("sort"),
+		Direction:   query.Get("direction"),
+	}
+
+	var err error
+	opt.Page, opt.PerPage, err = pageQuery(query)
+	return opt, err
+}
+
+func pageQuery(query url.Values) (int, int, error) {
+	var page, perPage int
+	var err error
+
+	if p := query.Get("page"); p != "" {
+		page, err = strconv.Atoi(p)
+		if err != nil {
+			return 0, 0, fmt.Errorf("Invalid page: %s", p)
+		}
+	}
+	if p := query.Get("per_page"); p != "" {
+		perPage, err = strconv.Atoi(p)
+		if err != nil {
+			return 0, 0, fmt.Errorf("Invalid per_page: %s", p)
+		}
+	}
+
+	return page, perPage, nil
+}
+
+// pageLinks points the pages github reports around a page to this service,
+// keeping the rest of the query of the request
+func pageLinks(u *url.URL, page domain.Pages) []domain.Link {
+	links := []domain.Link{}
+
+	add := func(rel string, n int) {
+		if n == 0 {
+			return
+		}
+		query := u.Query()
+		query.Set("page", strconv.Itoa(n))
+		link := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		links = append(links, domain.Link{Title: rel, URL: link.String()})
+	}
+
+	add("next", page.NextPage)
+	add("prev", page.PrevPage)
+	add("first", page.FirstPage)
+	add("last", page.LastPage)
+
+	return links
+}
+
+// fieldsQuery returns the repository fields in the fields query parameter, an
+// empty list selects every field
+func fieldsQuery(query url.Values) ([]string, error) {
+	fields := []string{}
+	if query.Get("fields") == "" {
+		return fields, nil
+	}
+
+	for _, field := range strings.Split(query.Get("fields"), ",") {
+		field = strings.TrimSpace(field)
+		if !repositoryFields[field] {
+			return nil, fmt.Errorf("Invalid field: %s", field)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// selectFields returns a map with only the fields of the repository, or the
+// repository itself when there are no fields
+func selectFields(repo domain.Repository, fields []string) interface{} {
+	if len(fields) == 0 {
+		return repo
+	}
+
+	repoB, _ := json.Marshal(repo)
+	all := map[string]json.RawMessage{}
+	json.Unmarshal(repoB, &all)
+
+	selected := map[string]json.RawMessage{}
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			selected[field] = value
+		}
+	}
+
+	return selected
+}
+
+func selectRepositoryFields(repos []domain.Repository, fields []string) []interface{} {
+	selected := []interface{}{}
+	for _, repo := range repos {
+		selected = append(selected, selectFields(repo, fields))
+	}
+
+	return selected
+}
+
+// jsonFields returns the JSON names of the fields of a struct type
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+
+	return fields
+}
+
+func setLinkHeader(res http.ResponseWriter, links []domain.Link) {
+	if len(links) == 0 {
+		return
+	}
+
+	header := []string{}
+	for _, link := range links {
+		header = append(header, fmt.Sprintf(`<%s>; rel="%s"`, link.URL, link.Title))
+	}
+	res.Header().Set("Link", strings.Join(header, ", "))
+}
+
+func isListError(err error) bool {
+	switch err {
+	case domain.ErrInvalidVisibility, domain.ErrInvalidAffiliation, domain.ErrInvalidRepoType,
+		domain.ErrRepoTypeConflict, domain.ErrInvalidSort, domain.ErrInvalidDirection,
+		domain.ErrInvalidPage, domain.ErrOwnReposFilter:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package interfaces_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/interfaces"
+	"github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+	"github.com/gorilla/mux"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// pagedRepository returns one of three pages of repositories
+type pagedRepository struct {
+	usecases.GithubRepository
+	opt *domain.RepositoryListOptions
+}
+
+func (repo pagedRepository) GetUser(username string) (*domain.User, error) {
+	return &domain.User{Username: "iasstest"}, nil
+}
+
+func (repo pagedRepository) ListRepos(username string, opt domain.RepositoryListOptions) (*domain.RepositoryPage, error) {
+	*repo.opt = opt
+	page := &domain.RepositoryPage{
+		Pages: domain.Pages{PrevPage: opt.Page - 1, FirstPage: 1, LastPage: 3},
+		Repositories: []domain.Repository{{
+			Name:        github.String("test"),
+			Language:    github.String("Go"),
+			Permissions: &domain.RepositoryPermissions{Admin: true, Push: true, Pull: true},
+		}},
+	}
+	if opt.Page < 3 {
+		page.NextPage = opt.Page + 1
+	}
+	return page, nil
+}
+
+var _ = Describe("Repositories", func() {
+	var opt domain.RepositoryListOptions
+	var router *mux.Router
+
+	BeforeEach(func() {
+		opt = domain.RepositoryListOptions{}
+		handler := WebServiceHandler{
+			GHInteractor: usecases.GHInteractor{GithubRepository: pagedRepository{opt: &opt}},
+		}
+		router = mux.NewRouter()
+		router.HandleFunc("/api/v1/repository/github/{username}/repos", handler.ShowRepos)
+	})
+
+	list := func(url string) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, httptest.NewRequest("GET", url, nil))
+		return res
+	}
+
+	It("Should pass the filters to github and link the pages around", func() {
+		res := list("/api/v1/repository/github/iasstest/repos?visibility=private&sort=pushed&page=2&per_page=10")
+		Ω(res.Code).Should(Equal(http.StatusOK))
+
+		Ω(opt.Visibility).Should(Equal("private"))
+		Ω(opt.Sort).Should(Equal("pushed"))
+		Ω(opt.Page).Should(Equal(2))
+		Ω(opt.PerPage).Should(Equal(10))
+
+		next := "/api/v1/repository/github/iasstest/repos?page=3&per_page=10&sort=pushed&visibility=private"
+		Ω(res.Header().Get("Link")).Should(ContainSubstring(`<` + next + `>; rel="next"`))
+		Ω(res.Body.String()).Should(ContainSubstring(`"name":"test","language":"Go"`))
+
+		body := struct {
+			Links []domain.Link `json:"links"`
+		}{}
+		Ω(json.Unmarshal(res.Body.Bytes(), &body)).Should(Succeed())
+		Ω(body.Links).Should(ContainElement(domain.Link{Title: "next", URL: next}))
+		Ω(body.Links).Should(ContainElement(domain.Link{Title: "last", URL: next}))
+	})
+
+	It("Should stream the repositories as NDJSON", func() {
+		req := httptest.NewRequest("GET", "/api/v1/repository/github/iasstest/repos?page=3", nil)
+		req.Header.Set("Accept", "application/x-ndjson")
+		res := httptest.NewRecorder()
+		router.ServeHTTP(res, req)
+
+		Ω(res.Code).Should(Equal(http.StatusOK))
+		Ω(res.Header().Get("Content-Type")).Should(Equal("application/x-ndjson"))
+		Ω(res.Flushed).Should(BeTrue())
+		Ω(res.Body.String()).Should(HavePrefix(`{"name":"test"`))
+		Ω(res.Body.String()).Should(HaveSuffix("}\n"))
+		Ω(opt.PerPage).Should(Equal(100))
+	})
+
+	It("Should select the fields of the repositories", func() {
+		res := list("/api/v1/repository/github/iasstest/repos?fields=name,permissions")
+		Ω(res.Code).Should(Equal(http.StatusOK))
+		Ω(res.Body.String()).Should(ContainSubstring(`"repositories":[{"name":"test","permissions":{"admin":true,"push":true,"pull":true}}]`))
+
+		res = list("/api/v1/repository/github/iasstest/repos?fields=name,stars")
+		Ω(res.Code).Should(Equal(http.StatusBadRequest))
+	})
+
+	It("Should reject invalid filters", func() {
+		res := list("/api/v1/repository/github/iasstest/repos?per_page=ten")
+		Ω(res.Code).Should(Equal(http.StatusBadRequest))
+
+		res = list("/api/v1/repository/github/iasstest/repos?direction=up")
+		Ω(res.Code).Should(Equal(http.StatusBadRequest))
+	})
+
+	It("Should give every request its own github repository", func() {
+		shared, err := NewGithubRepository("id", "secret", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		repos := []*GithubRepository{}
+		handler := WebServiceHandler{
+			Interactor: func(repo *GithubRepository) GHInteractor {
+				repos = append(repos, repo)
+				return usecases.GHInteractor{GithubRepository: pagedRepository{opt: &opt}}
+			},
+		}
+		router = mux.NewRouter()
+		router.Handle("/api/v1/repository/github/{username}/repos", Adapt(http.HandlerFunc(handler.ShowRepos), SetToken(shared)))
+
+		for _, token := range []string{"first", "second"} {
+			req := httptest.NewRequest("GET", "/api/v1/repository/github/iasstest/repos", nil)
+			req.Header.Set(domain.TokenHeader, token)
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+			Ω(res.Code).Should(Equal(http.StatusOK))
+		}
+
+		Ω(repos).Should(HaveLen(2))
+		Ω(repos[0]).ShouldNot(BeIdenticalTo(repos[1]))
+		Ω(repos).ShouldNot(ContainElement(BeIdenticalTo(shared)))
+	})
+})
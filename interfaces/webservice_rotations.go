@@ -0,0 +1,172 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type rotationRequestWrapper struct {
+	Rotation *domain.RotationRequest `json:"rotation"`
+}
+
+type rotationResponse struct {
+	Rotation   *domain.KeyRotation `json:"rotation"`
+	PrivateKey string              `json:"private_key,omitempty"`
+	Encryption string              `json:"encryption,omitempty"`
+}
+
+type rotationsResponse struct {
+	Rotations []domain.KeyRotation `json:"rotations"`
+}
+
+// ShowKeyRotations returns the deploy key rotations of a repository without
+// changing them, the ones past their grace period are completed by
+// CompleteExpiredRotations
+func (handler WebServiceHandler) ShowKeyRotations(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	rotations, err := handler.interactor(req).ShowKeyRotations(owner, repoName)
+	if err != nil {
+		writeRotationError(res, "Cannot retrieve rotations", err)
+		return
+	}
+
+	writeJSON(res, http.StatusOK, rotationsResponse{Rotations: rotations})
+}
+
+// ShowExpiredRotations returns the rotations of every repository that are past
+// their grace period. The service keeps no github tokens, so it cannot delete
+// their old keys on its own: a scheduler with a token for each repository
+// completes them in CompleteExpiredRotations.
+func (handler WebServiceHandler) ShowExpiredRotations(res http.ResponseWriter, req *http.Request) {
+	rotations, err := handler.GHInteractor.ShowExpiredRotations()
+	if err != nil {
+		writeRotationError(res, "Cannot retrieve rotations", err)
+		return
+	}
+
+	writeJSON(res, http.StatusOK, rotationsResponse{Rotations: rotations})
+}
+
+// CompleteExpiredRotations deletes the old keys of the rotations of a
+// repository past their grace period and returns the completed rotations
+func (handler WebServiceHandler) CompleteExpiredRotations(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	rotations, err := handler.interactor(req).CompleteExpiredRotations(owner, repoName)
+	if err != nil {
+		writeRotationError(res, "Cannot complete rotations", err)
+		return
+	}
+
+	writeJSON(res, http.StatusOK, rotationsResponse{Rotations: rotations})
+}
+
+// StartKeyRotation replaces the deploy key with the title of the request, the
+// response has the new private key when the service generated it
+func (handler WebServiceHandler) StartKeyRotation(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var request rotationRequestWrapper
+	err := decoder.Decode(&request)
+	if err != nil || request.Rotation == nil || request.Rotation.Title == nil || *request.Rotation.Title == "" {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	rotation, key, err := handler.interactor(req).StartKeyRotation(owner, repoName, *request.Rotation)
+	if err != nil {
+		writeRotationError(res, "Cannot rotate deploy key", err)
+		return
+	}
+
+	writeRotation(res, http.StatusCreated, rotation, key)
+}
+
+// ResumeKeyRotation continues an interrupted rotation, the body is only needed
+// to encrypt the private key of a replacement generated by the service
+func (handler WebServiceHandler) ResumeKeyRotation(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var request rotationRequestWrapper
+	err := decoder.Decode(&request)
+	if err != nil && err != io.EOF {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	keyRequest := domain.KeyRequest{}
+	if request.Rotation != nil {
+		keyRequest = request.Rotation.KeyRequest
+	}
+
+	rotation, key, err := handler.interactor(req).ResumeKeyRotation(owner, repoName, vars["id"], keyRequest)
+	if err != nil {
+		writeRotationError(res, "Cannot resume rotation", err)
+		return
+	}
+
+	writeRotation(res, http.StatusOK, rotation, key)
+}
+
+// ConfirmKeyRotation deletes the old key of a rotation
+func (handler WebServiceHandler) ConfirmKeyRotation(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	rotation, err := handler.interactor(req).ConfirmKeyRotation(owner, repoName, vars["id"])
+	if err != nil {
+		writeRotationError(res, "Cannot confirm rotation", err)
+		return
+	}
+
+	writeRotation(res, http.StatusOK, rotation, nil)
+}
+
+func writeRotation(res http.ResponseWriter, status int, rotation *domain.KeyRotation, key *domain.GeneratedKey) {
+	response := rotationResponse{Rotation: rotation}
+	if key != nil {
+		response.PrivateKey = key.PrivateKey
+		response.Encryption = key.Encryption
+		res.Header().Set("Cache-Control", "no-store")
+	}
+
+	writeJSON(res, status, response)
+}
+
+func writeRotationError(res http.ResponseWriter, msg string, err error) {
+	status := http.StatusInternalServerError
+	switch err {
+	case domain.ErrKeyNotFound, domain.ErrRotationNotFound:
+		status = http.StatusNotFound
+	case domain.ErrAmbiguousKey, domain.ErrRotationInProgress, domain.ErrRotationState:
+		status = http.StatusConflict
+	case domain.ErrInvalidKeyType, domain.ErrInvalidRecipient, domain.ErrKeyEncryption,
+		domain.ErrInvalidKey, domain.ErrUnsupportedKey, domain.ErrWeakKey:
+		status = 422
+	case domain.ErrNoRotationStore:
+		status = http.StatusNotImplemented
+	}
+
+	writeError(res, status, fmt.Sprintf("%s: %s", msg, err.Error()))
+}
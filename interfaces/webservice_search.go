@@ -0,0 +1,112 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+type repositorySearchResponse struct {
+	Total        int           `json:"total_count"`
+	Incomplete   bool          `json:"incomplete_results"`
+	Repositories []interface{} `json:"repositories"`
+	Links        []domain.Link `json:"links,omitempty"`
+}
+
+type codeSearchResponse struct {
+	Total      int                 `json:"total_count"`
+	Incomplete bool                `json:"incomplete_results"`
+	Results    []domain.CodeResult `json:"code_results"`
+	Links      []domain.Link       `json:"links,omitempty"`
+}
+
+// SearchRepos searches the repositories of the user and its organizations. The
+// q query parameter takes the github search syntax, owner limits the search to
+// the user or one of its organizations, fields selects the fields of every
+// repository and sort, order, page and per_page are passed to github.
+func (handler WebServiceHandler) SearchRepos(res http.ResponseWriter, req *http.Request) {
+	opt, err := searchOptions(req.URL.Query())
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields, err := fieldsQuery(req.URL.Query())
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	search, err := handler.interactor(req).SearchRepos(opt)
+	if isSearchError(err) {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot search repositories: %s", err.Error()))
+		return
+	}
+
+	links := pageLinks(req.URL, search.Pages)
+	setLinkHeader(res, links)
+
+	writeJSON(res, http.StatusOK, repositorySearchResponse{
+		Total:        search.Total,
+		Incomplete:   search.Incomplete,
+		Repositories: selectRepositoryFields(search.Repositories, fields),
+		Links:        links,
+	})
+}
+
+// SearchCode searches the files in the repositories of the user and its
+// organizations with the same query parameters as SearchRepos
+func (handler WebServiceHandler) SearchCode(res http.ResponseWriter, req *http.Request) {
+	opt, err := searchOptions(req.URL.Query())
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	search, err := handler.interactor(req).SearchCode(opt)
+	if isSearchError(err) {
+		writeError(res, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot search code: %s", err.Error()))
+		return
+	}
+
+	links := pageLinks(req.URL, search.Pages)
+	setLinkHeader(res, links)
+
+	writeJSON(res, http.StatusOK, codeSearchResponse{
+		Total:      search.Total,
+		Incomplete: search.Incomplete,
+		Results:    search.Results,
+		Links:      links,
+	})
+}
+
+func searchOptions(query url.Values) (domain.SearchOptions, error) {
+	opt := domain.SearchOptions{
+		Query: query.Get("q"),
+		Owner: query.Get("owner"),
+		Sort:  query.Get("sort"),
+		Order: query.Get("order"),
+	}
+
+	var err error
+	opt.Page, opt.PerPage, err = pageQuery(query)
+	return opt, err
+}
+
+func isSearchError(err error) bool {
+	switch err {
+	case domain.ErrEmptyQuery, domain.ErrSearchQualifier, domain.ErrSearchScope,
+		domain.ErrInvalidSearchSort, domain.ErrInvalidOrder, domain.ErrInvalidPage:
+		return true
+	}
+	return false
+}
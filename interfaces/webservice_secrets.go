@@ -0,0 +1,110 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type secretsWrapper struct {
+	Secrets []domain.Secret `json:"secrets"`
+}
+
+type secretWrapper struct {
+	Secret secretValue `json:"secret"`
+}
+
+type secretValue struct {
+	Value string `json:"value"`
+}
+
+type secretNameWrapper struct {
+	Secret domain.Secret `json:"secret"`
+}
+
+// ShowSecrets returns the names of the Actions secrets of a repository, or of
+// one of its environments, github never returns their values
+func (handler WebServiceHandler) ShowSecrets(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+	environment := vars["environment"]
+
+	secrets, err := handler.interactor(req).ShowSecrets(owner, repoName, environment)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve secrets: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, secretsWrapper{Secrets: secrets})
+}
+
+// SetSecret encrypts the value of the request and creates or updates the
+// secret with it, the value is never written back or logged
+func (handler WebServiceHandler) SetSecret(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+	environment := vars["environment"]
+	name := vars["name"]
+
+	decoder := json.NewDecoder(req.Body)
+	var secret secretWrapper
+	err := decoder.Decode(&secret)
+	if err != nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	created, err := handler.interactor(req).SetSecret(owner, repoName, environment, name, []byte(secret.Secret.Value))
+	if isSecretError(err) {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot set secret: %s", err.Error()))
+		return
+	}
+
+	if !created {
+		res.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, secretNameWrapper{Secret: domain.Secret{Name: name}})
+}
+
+// DeleteSecret removes an Actions secret of a repository or of one of its
+// environments
+func (handler WebServiceHandler) DeleteSecret(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+	environment := vars["environment"]
+	name := vars["name"]
+
+	err := handler.interactor(req).DeleteSecret(owner, repoName, environment, name)
+	if err == domain.ErrInvalidSecretName {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot delete secret: %s", err.Error()))
+		return
+	}
+
+	res.WriteHeader(http.StatusNoContent)
+}
+
+func isSecretError(err error) bool {
+	switch err {
+	case domain.ErrInvalidSecretName, domain.ErrEmptySecret, domain.ErrSecretTooLarge:
+		return true
+	}
+	return false
+}
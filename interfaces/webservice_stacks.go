@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type stackReportWrapper struct {
+	Report *domain.StackReport `json:"stack_report"`
+}
+
+// ShowStacks returns the stacks detected in a repository at the ref query
+// parameter, or at its default branch
+func (handler WebServiceHandler) ShowStacks(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	report, err := handler.interactor(req).DetectStacks(owner, repoName, req.URL.Query().Get("ref"))
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot detect stacks: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, stackReportWrapper{Report: report})
+}
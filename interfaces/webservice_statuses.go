@@ -0,0 +1,64 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type statusWrapper struct {
+	Status *domain.CommitStatus `json:"status"`
+}
+
+type combinedStatusResponse struct {
+	CombinedStatus *domain.CombinedStatus `json:"combined_status"`
+}
+
+// CreateStatus posts a commit status with its context, state, description and target URL
+func (handler WebServiceHandler) CreateStatus(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+	sha := vars["sha"]
+
+	decoder := json.NewDecoder(req.Body)
+	var status statusWrapper
+	err := decoder.Decode(&status)
+	if err != nil || status.Status == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	s, err := handler.interactor(req).CreateStatus(username, repoName, sha, status.Status)
+	if err == domain.ErrInvalidCommitState {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot create status: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusCreated, statusWrapper{Status: s})
+}
+
+// ShowCombinedStatus returns the combined status of a ref
+func (handler WebServiceHandler) ShowCombinedStatus(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	username := vars["username"]
+	repoName := vars["repo"]
+	ref := vars["ref"]
+
+	s, err := handler.interactor(req).ShowCombinedStatus(username, repoName, ref)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve status: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, combinedStatusResponse{CombinedStatus: s})
+}
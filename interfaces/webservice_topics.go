@@ -0,0 +1,59 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/gorilla/mux"
+)
+
+type topicsWrapper struct {
+	Topics []string `json:"topics"`
+}
+
+// ShowTopics returns the topics of a repository
+func (handler WebServiceHandler) ShowTopics(res http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	topics, err := handler.interactor(req).ShowTopics(owner, repoName)
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot retrieve topics: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, topicsWrapper{Topics: topics})
+}
+
+// ReplaceTopics replaces every topic of a repository with the ones of the
+// request, an empty list removes them all
+func (handler WebServiceHandler) ReplaceTopics(res http.ResponseWriter, req *http.Request) {
+	defer req.Body.Close()
+
+	vars := mux.Vars(req)
+	owner := vars["username"]
+	repoName := vars["repo"]
+
+	decoder := json.NewDecoder(req.Body)
+	var topics topicsWrapper
+	err := decoder.Decode(&topics)
+	if err != nil || topics.Topics == nil {
+		writeError(res, 422, "cannot process request")
+		return
+	}
+
+	replaced, err := handler.interactor(req).ReplaceTopics(owner, repoName, topics.Topics)
+	if err == domain.ErrInvalidTopic || err == domain.ErrTooManyTopics {
+		writeError(res, 422, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(res, http.StatusInternalServerError, fmt.Sprintf("Cannot replace topics: %s", err.Error()))
+		return
+	}
+
+	writeJSON(res, http.StatusOK, topicsWrapper{Topics: replaced})
+}
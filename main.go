@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"log"
 	"net/http"
+	"time"
 
+	"github.com/Tinker-Ware/gh-service/domain"
 	"github.com/Tinker-Ware/gh-service/infrastructure"
 	"github.com/Tinker-Ware/gh-service/interfaces"
 	"github.com/Tinker-Ware/gh-service/usecases"
@@ -37,10 +40,57 @@ func main() {
 	ghinteractor := usecases.GHInteractor{
 		GithubRepository: ghrepo,
 	}
+	if config.HookSecretsPath != "" {
+		store, err := infrastructure.NewHookSecretStore(config.HookSecretsPath)
+		if err != nil {
+			panic(err.Error())
+		}
+		ghinteractor.HookSecrets = store
+	}
+	if config.RotationsPath != "" {
+		store, err := infrastructure.NewRotationStore(config.RotationsPath)
+		if err != nil {
+			panic(err.Error())
+		}
+		ghinteractor.Rotations = store
+	}
+	if config.RotationGracePeriod != "" {
+		ghinteractor.RotationGracePeriod, err = time.ParseDuration(config.RotationGracePeriod)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+
+	if len(config.ReadinessChecks) > 0 {
+		ghinteractor.ReadinessChecks, err = usecases.SelectReadinessChecks(config.ReadinessChecks)
+		if err != nil {
+			panic(err.Error())
+		}
+	}
+
+	webhooks := usecases.NewWebhookDispatcher()
+	if config.DeliveriesPath != "" {
+		store, err := infrastructure.NewDeliveryStore(config.DeliveriesPath)
+		if err != nil {
+			panic(err.Error())
+		}
+		webhooks.Store = store
+	}
+	webhooks.Handle(usecases.AllEvents, func(event domain.WebhookEvent) error {
+		log.Printf("Received %s event, delivery %s", event.Type, event.DeliveryID)
+		return nil
+	})
 
 	handler := interfaces.WebServiceHandler{
 		GHInteractor: ghinteractor,
-		APIHost:      config.APIHost,
+		Interactor: func(repo *interfaces.GithubRepository) interfaces.GHInteractor {
+			interactor := ghinteractor
+			interactor.GithubRepository = repo
+			return interactor
+		},
+		APIHost:        config.APIHost,
+		WebhookSecrets: config.WebhookSecrets,
+		Webhooks:       webhooks,
 	}
 
 	// Add CORS Support
@@ -49,15 +99,92 @@ func main() {
 
 	r := mux.NewRouter()
 
+	r.Handle("/webhooks/github", interfaces.Adapt(http.HandlerFunc(handler.ReceiveGithubEvent), interfaces.Notify())).Methods("POST")
+
+	admin := r.PathPrefix("/admin/webhooks").Subrouter()
+	admin.Handle("/deliveries", interfaces.Adapt(http.HandlerFunc(handler.ShowDeliveries), interfaces.Notify(), interfaces.AdminToken(config.AdminToken))).Methods("GET")
+	admin.Handle("/deliveries/redeliver", interfaces.Adapt(http.HandlerFunc(handler.RedeliverRange), interfaces.Notify(), interfaces.AdminToken(config.AdminToken))).Methods("POST")
+	admin.Handle("/deliveries/{id}", interfaces.Adapt(http.HandlerFunc(handler.ShowDelivery), interfaces.Notify(), interfaces.AdminToken(config.AdminToken))).Methods("GET")
+	admin.Handle("/deliveries/{id}/redeliver", interfaces.Adapt(http.HandlerFunc(handler.RedeliverDelivery), interfaces.Notify(), interfaces.AdminToken(config.AdminToken))).Methods("POST")
+
+	rotations := r.PathPrefix("/admin/rotations").Subrouter()
+	rotations.Handle("/expired", interfaces.Adapt(http.HandlerFunc(handler.ShowExpiredRotations), interfaces.Notify(), interfaces.AdminToken(config.AdminToken))).Methods("GET")
+
 	subrouter := r.PathPrefix("/api/v1/repository/github").Subrouter()
 	subrouter.Handle("/oauth", interfaces.Adapt(http.HandlerFunc(handler.Callback), interfaces.Notify())).Methods("POST")
+	// Organization hooks are registered before the repository routes they overlap with
+	subrouter.Handle("/user/{username}/orgs", interfaces.Adapt(http.HandlerFunc(handler.ShowOrganizations), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/orgs/{org}/repos", interfaces.Adapt(http.HandlerFunc(handler.ShowOrgRepos), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/orgs/{org}/teams", interfaces.Adapt(http.HandlerFunc(handler.ShowTeams), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/orgs/{username}/hooks", interfaces.Adapt(http.HandlerFunc(handler.ShowHooks), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/orgs/{username}/hooks", interfaces.Adapt(http.HandlerFunc(handler.CreateHook), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/orgs/{username}/hooks/{id}", interfaces.Adapt(http.HandlerFunc(handler.UpdateHook), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PATCH")
+	subrouter.Handle("/orgs/{username}/hooks/{id}", interfaces.Adapt(http.HandlerFunc(handler.DeleteHook), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/orgs/{username}/hooks/{id}/pings", interfaces.Adapt(http.HandlerFunc(handler.PingHook), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/user/{username}/keys", interfaces.Adapt(http.HandlerFunc(handler.ShowKeys), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/user/{username}/keys", interfaces.Adapt(http.HandlerFunc(handler.CreateKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/user/{username}/keys/{id}", interfaces.Adapt(http.HandlerFunc(handler.ShowKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/user/{username}/keys/{id}", interfaces.Adapt(http.HandlerFunc(handler.DeleteKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/user/{username}/gpg_keys", interfaces.Adapt(http.HandlerFunc(handler.ShowGPGKeys), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/user/{username}/gpg_keys", interfaces.Adapt(http.HandlerFunc(handler.CreateGPGKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/user/{username}/gpg_keys/{id}", interfaces.Adapt(http.HandlerFunc(handler.DeleteGPGKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/user/{username}/search/repositories", interfaces.Adapt(http.HandlerFunc(handler.SearchRepos), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/user/{username}/search/code", interfaces.Adapt(http.HandlerFunc(handler.SearchCode), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
 	subrouter.Handle("/{username}/repos", interfaces.Adapt(http.HandlerFunc(handler.ShowRepos), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))) //.Methods("GET")
 	subrouter.Handle("/{username}/{repo}", interfaces.Adapt(http.HandlerFunc(handler.ShowRepo), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))) //.Methods("GET")
 	subrouter.Handle("/{username}/{repo}/deploy_key", interfaces.Adapt(http.HandlerFunc(handler.CreateRepoDeployKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/deploy_keys", interfaces.Adapt(http.HandlerFunc(handler.ShowRepoDeployKeys), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/deploy_keys", interfaces.Adapt(http.HandlerFunc(handler.CreateRepoDeployKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/deploy_keys/generate", interfaces.Adapt(http.HandlerFunc(handler.GenerateRepoDeployKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/deploy_keys/rotations", interfaces.Adapt(http.HandlerFunc(handler.ShowKeyRotations), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/deploy_keys/rotations", interfaces.Adapt(http.HandlerFunc(handler.StartKeyRotation), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/deploy_keys/rotations/complete", interfaces.Adapt(http.HandlerFunc(handler.CompleteExpiredRotations), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/deploy_keys/rotations/{id}/resume", interfaces.Adapt(http.HandlerFunc(handler.ResumeKeyRotation), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/deploy_keys/rotations/{id}/confirm", interfaces.Adapt(http.HandlerFunc(handler.ConfirmKeyRotation), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/deploy_keys/{id}", interfaces.Adapt(http.HandlerFunc(handler.ShowRepoDeployKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/deploy_keys/{id}", interfaces.Adapt(http.HandlerFunc(handler.DeleteRepoDeployKey), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/{username}/{repo}/archive", interfaces.Adapt(http.HandlerFunc(handler.ArchiveRepo), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/archive", interfaces.Adapt(http.HandlerFunc(handler.UnarchiveRepo), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/{username}/{repo}/topics", interfaces.Adapt(http.HandlerFunc(handler.ShowTopics), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/topics", interfaces.Adapt(http.HandlerFunc(handler.ReplaceTopics), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PUT")
+	subrouter.Handle("/{username}/{repo}/stacks", interfaces.Adapt(http.HandlerFunc(handler.ShowStacks), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/readiness", interfaces.Adapt(http.HandlerFunc(handler.ShowReadiness), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/actions/secrets", interfaces.Adapt(http.HandlerFunc(handler.ShowSecrets), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/actions/secrets/{name}", interfaces.Adapt(http.HandlerFunc(handler.SetSecret), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PUT")
+	subrouter.Handle("/{username}/{repo}/actions/secrets/{name}", interfaces.Adapt(http.HandlerFunc(handler.DeleteSecret), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/{username}/{repo}/environments/{environment}/secrets", interfaces.Adapt(http.HandlerFunc(handler.ShowSecrets), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/environments/{environment}/secrets/{name}", interfaces.Adapt(http.HandlerFunc(handler.SetSecret), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PUT")
+	subrouter.Handle("/{username}/{repo}/environments/{environment}/secrets/{name}", interfaces.Adapt(http.HandlerFunc(handler.DeleteSecret), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/{username}/{repo}/transfer", interfaces.Adapt(http.HandlerFunc(handler.TransferRepo), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/fork", interfaces.Adapt(http.HandlerFunc(handler.ForkRepo), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/branches", interfaces.Adapt(http.HandlerFunc(handler.ShowBranches), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/branches", interfaces.Adapt(http.HandlerFunc(handler.CreateBranch), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/branches/{branch:.+}/protection", interfaces.Adapt(http.HandlerFunc(handler.ShowBranchProtection), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/branches/{branch:.+}/protection", interfaces.Adapt(http.HandlerFunc(handler.ApplyBranchProtection), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PUT")
+	subrouter.Handle("/{username}/{repo}/branches/{branch:.+}", interfaces.Adapt(http.HandlerFunc(handler.DeleteBranch), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/{username}/{repo}/default_branch", interfaces.Adapt(http.HandlerFunc(handler.SetDefaultBranch), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PUT")
+	subrouter.Handle("/{username}/{repo}/tags", interfaces.Adapt(http.HandlerFunc(handler.CreateTag), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/releases", interfaces.Adapt(http.HandlerFunc(handler.ShowReleases), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/releases", interfaces.Adapt(http.HandlerFunc(handler.CreateRelease), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/releases/{id}", interfaces.Adapt(http.HandlerFunc(handler.UpdateRelease), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PATCH")
+	subrouter.Handle("/{username}/{repo}/releases/{id}/assets", interfaces.Adapt(http.HandlerFunc(handler.UploadReleaseAsset), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/deployments", interfaces.Adapt(http.HandlerFunc(handler.ShowDeployments), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/deployments", interfaces.Adapt(http.HandlerFunc(handler.CreateDeployment), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/deployments/{id}/statuses", interfaces.Adapt(http.HandlerFunc(handler.UpdateDeploymentStatus), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/statuses/{sha}", interfaces.Adapt(http.HandlerFunc(handler.CreateStatus), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/commits/{ref:.+}/status", interfaces.Adapt(http.HandlerFunc(handler.ShowCombinedStatus), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/hooks", interfaces.Adapt(http.HandlerFunc(handler.ShowHooks), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/hooks", interfaces.Adapt(http.HandlerFunc(handler.CreateHook), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/hooks/{id}", interfaces.Adapt(http.HandlerFunc(handler.UpdateHook), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PATCH")
+	subrouter.Handle("/{username}/{repo}/hooks/{id}", interfaces.Adapt(http.HandlerFunc(handler.DeleteHook), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/{username}/{repo}/hooks/{id}/pings", interfaces.Adapt(http.HandlerFunc(handler.PingHook), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("POST")
+	subrouter.Handle("/{username}/{repo}/collaborators", interfaces.Adapt(http.HandlerFunc(handler.ShowCollaborators), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/collaborators/{user}", interfaces.Adapt(http.HandlerFunc(handler.AddCollaborator), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PUT")
+	subrouter.Handle("/{username}/{repo}/collaborators/{user}", interfaces.Adapt(http.HandlerFunc(handler.RemoveCollaborator), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/{username}/{repo}/invitations", interfaces.Adapt(http.HandlerFunc(handler.ShowInvitations), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("GET")
+	subrouter.Handle("/{username}/{repo}/invitations/{id}", interfaces.Adapt(http.HandlerFunc(handler.CancelInvitation), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("DELETE")
+	subrouter.Handle("/{username}/{repo}/teams/{id}", interfaces.Adapt(http.HandlerFunc(handler.GrantTeamAccess), interfaces.Notify(), interfaces.GetToken(ghrepo, config.APIHost, config.Salt))).Methods("PUT")
 	// subrouter.Handle("/user/{username}/repos", interfaces.Adapt(http.HandlerFunc(handler.CreateRepo), interfaces.Notify(), interfaces.SetToken(ghrepo))).Methods("POST")
-	// subrouter.Handle("/user/{username}/keys", interfaces.Adapt(http.HandlerFunc(handler.CreateRepo), interfaces.Notify(), interfaces.SetToken(ghrepo))).Methods("GET")
-	// subrouter.Handle("/user/{username}/keys", interfaces.Adapt(http.HandlerFunc(handler.CreateKey), interfaces.Notify(), interfaces.SetToken(ghrepo))).Methods("POST")
-	// subrouter.Handle("/user/{username}/keys/{id}", interfaces.Adapt(http.HandlerFunc(handler.ShowKey), interfaces.Notify(), interfaces.SetToken(ghrepo))).Methods("GET")
 	// subrouter.Handle("/user/{username}/{repo}/addfile", interfaces.Adapt(http.HandlerFunc(handler.AddFileToRepository), interfaces.Notify(), interfaces.SetToken(ghrepo))).Methods("POST")
 	// subrouter.Handle("/user/{username}/{repo}/addfiles", interfaces.Adapt(http.HandlerFunc(handler.AddMultipleFilesToRepository), interfaces.Notify(), interfaces.SetToken(ghrepo))).Methods("POST")
 	// subrouter.HandleFunc("/user_info", handler.GetCurrentUser).Methods("GET")
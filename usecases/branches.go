@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+func (interactor GHInteractor) ShowBranches(username, repo string) ([]domain.Branch, error) {
+	branches, err := interactor.GithubRepository.ListBranches(username, repo)
+	if err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+func (interactor GHInteractor) CreateBranch(username, repo, branch, from string) (*domain.Branch, error) {
+	b, err := interactor.GithubRepository.CreateBranch(username, repo, branch, from)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (interactor GHInteractor) DeleteBranch(username, repo, branch string) error {
+	return interactor.GithubRepository.DeleteBranch(username, repo, branch)
+}
+
+func (interactor GHInteractor) SetDefaultBranch(username, repo, branch string) (*domain.Repository, error) {
+	r, err := interactor.GithubRepository.SetDefaultBranch(username, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (interactor GHInteractor) ShowBranchProtection(username, repo, branch string) (*domain.BranchProtection, error) {
+	p, err := interactor.GithubRepository.GetBranchProtection(username, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeProtection(p), nil
+}
+
+// ApplyBranchProtection makes the protection of a branch match policy, github is
+// only updated when the current protection differs from the policy
+func (interactor GHInteractor) ApplyBranchProtection(username, repo, branch string, policy *domain.BranchProtection) (*domain.BranchProtection, error) {
+	policy = normalizeProtection(policy)
+
+	current, err := interactor.GithubRepository.GetBranchProtection(username, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	if reflect.DeepEqual(normalizeProtection(current), policy) {
+		return policy, nil
+	}
+
+	p, err := interactor.GithubRepository.UpdateBranchProtection(username, repo, branch, policy)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeProtection(p), nil
+}
+
+// normalizeProtection returns a copy of p with sorted, non nil lists so policies
+// can be compared and sent to github as they are
+func normalizeProtection(p *domain.BranchProtection) *domain.BranchProtection {
+	n := &domain.BranchProtection{
+		EnforceAdmins: p.EnforceAdmins,
+	}
+
+	if p.RequiredStatusChecks != nil {
+		n.RequiredStatusChecks = &domain.RequiredStatusChecks{
+			Strict:   p.RequiredStatusChecks.Strict,
+			Contexts: sortedCopy(p.RequiredStatusChecks.Contexts),
+		}
+	}
+
+	if p.RequiredReviews != nil {
+		reviews := *p.RequiredReviews
+		n.RequiredReviews = &reviews
+	}
+
+	if p.Restrictions != nil {
+		n.Restrictions = &domain.PushRestrictions{
+			Users: sortedCopy(p.Restrictions.Users),
+			Teams: sortedCopy(p.Restrictions.Teams),
+		}
+	}
+
+	return n
+}
+
+func sortedCopy(values []string) []string {
+	c := make([]string, len(values))
+	copy(c, values)
+	sort.Strings(c)
+	return c
+}
@@ -0,0 +1,51 @@
+package usecases
+
+import "github.com/Tinker-Ware/gh-service/domain"
+
+var repoPermissions = map[string]bool{
+	domain.PermissionPull:  true,
+	domain.PermissionPush:  true,
+	domain.PermissionAdmin: true,
+}
+
+func (interactor GHInteractor) ShowCollaborators(owner, repo string) ([]domain.Collaborator, error) {
+	collaborators, err := interactor.GithubRepository.ListCollaborators(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+// AddCollaborator grants user a permission on the repository, push when it is
+// empty. Users that are not collaborators yet are invited and the invitation is
+// returned.
+func (interactor GHInteractor) AddCollaborator(owner, repo, user, permission string) (*domain.Invitation, error) {
+	if permission == "" {
+		permission = domain.PermissionPush
+	}
+	if !repoPermissions[permission] {
+		return nil, domain.ErrInvalidPermission
+	}
+
+	invitation, err := interactor.GithubRepository.AddCollaborator(owner, repo, user, permission)
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (interactor GHInteractor) RemoveCollaborator(owner, repo, user string) error {
+	return interactor.GithubRepository.RemoveCollaborator(owner, repo, user)
+}
+
+func (interactor GHInteractor) ShowInvitations(owner, repo string) ([]domain.Invitation, error) {
+	invitations, err := interactor.GithubRepository.ListInvitations(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (interactor GHInteractor) CancelInvitation(owner, repo string, id int) error {
+	return interactor.GithubRepository.DeleteInvitation(owner, repo, id)
+}
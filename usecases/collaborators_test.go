@@ -0,0 +1,41 @@
+package usecases_test
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Add collaborators", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	It("Should grant push when no permission is requested", func() {
+		_, err := interactor.AddCollaborator("iasstest", "test", "ops", "")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(repo.Permissions["ops"]).Should(Equal(domain.PermissionPush))
+	})
+
+	It("Should reject an unknown permission", func() {
+		_, err := interactor.AddCollaborator("iasstest", "test", "ops", "owner")
+		Ω(err).Should(Equal(domain.ErrInvalidPermission))
+		Ω(repo.Permissions).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("Grant team access", func() {
+	It("Should reject an unknown permission", func() {
+		repo := newFakeRepository()
+		interactor := GHInteractor{GithubRepository: repo}
+		err := interactor.GrantTeamAccess(1, "iasstest", "test", "maintain")
+		Ω(err).Should(Equal(domain.ErrInvalidPermission))
+		Ω(repo.Permissions).Should(BeEmpty())
+	})
+})
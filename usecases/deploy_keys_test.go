@@ -0,0 +1,266 @@
+package usecases_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"github.com/Tinker-Ware/gh-service/infrastructure"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func readKey(name string) string {
+	data, err := ioutil.ReadFile("testdata/keys/" + name)
+	if err != nil {
+		panic(err.Error())
+	}
+	return strings.TrimSpace(string(data))
+}
+
+var _ = Describe("Validate keys", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	It("Should reject keys that are not safe to use", func() {
+		for file, expected := range map[string]error{
+			"dsa.pub":      domain.ErrUnsupportedKey,
+			"rsa_1024.pub": domain.ErrWeakKey,
+		} {
+			key := domain.Key{Key: github.String(readKey(file))}
+			err := interactor.AddDeployKey("iasstest", "test", &key)
+			Ω(err).Should(Equal(expected))
+		}
+
+		key := domain.Key{Key: github.String("ssh-rsa notakey")}
+		err := interactor.AddDeployKey("iasstest", "test", &key)
+		Ω(err).Should(Equal(domain.ErrInvalidKey))
+
+		Ω(repo.Keys).Should(BeEmpty())
+	})
+
+	It("Should add the fingerprint of the key", func() {
+		key := domain.Key{Title: github.String("rsa"), Key: github.String(readKey("rsa_2048.pub"))}
+		err := interactor.AddDeployKey("iasstest", "test", &key)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(*key.Fingerprint).Should(HavePrefix("SHA256:"))
+		Ω(repo.Keys).Should(HaveLen(1))
+	})
+
+	It("Should return an identical key instead of creating a duplicate", func() {
+		key := domain.Key{Title: github.String("first"), Key: github.String(readKey("ed25519_old.pub"))}
+		Ω(interactor.AddDeployKey("iasstest", "test", &key)).Should(Succeed())
+
+		// Same key with another comment
+		duplicate := domain.Key{
+			Title: github.String("second"),
+			Key:   github.String(strings.TrimSuffix(readKey("ed25519_old.pub"), " old") + " other"),
+		}
+		Ω(interactor.AddDeployKey("iasstest", "test", &duplicate)).Should(Succeed())
+		Ω(*duplicate.ID).Should(Equal(*key.ID))
+		Ω(*duplicate.Title).Should(Equal("first"))
+		Ω(repo.Keys).Should(HaveLen(1))
+	})
+
+	It("Should not return an identical key with another access", func() {
+		key := domain.Key{Title: github.String("read"), Key: github.String(readKey("ed25519_old.pub")), ReadOnly: github.Bool(true)}
+		Ω(interactor.AddDeployKey("iasstest", "test", &key)).Should(Succeed())
+
+		writable := domain.Key{Title: github.String("write"), Key: github.String(readKey("ed25519_old.pub")), ReadOnly: github.Bool(false)}
+		err := interactor.AddDeployKey("iasstest", "test", &writable)
+		Ω(err).Should(Equal(domain.ErrKeyAccessConflict))
+		Ω(writable.ID).Should(BeNil())
+		Ω(repo.Keys).Should(HaveLen(1))
+	})
+})
+
+var _ = Describe("Rotate deploy keys", func() {
+	var dir string
+	var repo *fakeRepository
+	var interactor GHInteractor
+	oldKey := readKey("ed25519_old.pub")
+	newKey := readKey("ed25519_new.pub")
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "rotations")
+		Ω(err).ShouldNot(HaveOccurred())
+		store, err := infrastructure.NewRotationStore(dir)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		repo = newFakeRepository()
+		repo.AddDeployKey("iasstest", "test", &domain.Key{
+			Title:    github.String("provisioning"),
+			Key:      github.String(oldKey),
+			ReadOnly: github.Bool(false),
+		})
+
+		interactor = GHInteractor{
+			GithubRepository:    repo,
+			Rotations:           store,
+			RotationGracePeriod: time.Hour,
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	request := func(publicKey *string) domain.RotationRequest {
+		r := domain.RotationRequest{PublicKey: publicKey}
+		r.Title = github.String("provisioning")
+		return r
+	}
+
+	It("Should replace the key after the rotation is confirmed", func() {
+		rotation, generated, err := interactor.StartKeyRotation("iasstest", "test", request(nil))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(generated.PrivateKey).ShouldNot(BeEmpty())
+		Ω(rotation.State).Should(Equal(domain.RotationCreated))
+		Ω(rotation.OldKeyID).Should(Equal(1))
+		Ω(*rotation.NewKeyID).Should(Equal(2))
+		Ω(repo.Keys).Should(HaveLen(2))
+		Ω(*repo.Keys[1].ReadOnly).Should(BeFalse())
+
+		_, _, err = interactor.StartKeyRotation("iasstest", "test", request(nil))
+		Ω(err).Should(Equal(domain.ErrRotationInProgress))
+
+		rotation, err = interactor.ConfirmKeyRotation("iasstest", "test", rotation.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rotation.State).Should(Equal(domain.RotationCompleted))
+		Ω(repo.Keys).Should(HaveLen(1))
+		Ω(*repo.Keys[0].ID).Should(Equal(2))
+	})
+
+	It("Should register the public key of the caller", func() {
+		rotation, generated, err := interactor.StartKeyRotation("iasstest", "test", request(&newKey))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(generated).Should(BeNil())
+		Ω(*repo.Keys[1].Key).Should(Equal(newKey))
+		Ω(*rotation.NewKeyID).Should(Equal(2))
+	})
+
+	It("Should delete the old key when resumed after the grace period", func() {
+		rotation, _, err := interactor.StartKeyRotation("iasstest", "test", request(&newKey))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		rotation, _, err = interactor.ResumeKeyRotation("iasstest", "test", rotation.ID, domain.KeyRequest{})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rotation.State).Should(Equal(domain.RotationCreated))
+		Ω(repo.Keys).Should(HaveLen(2))
+
+		past := time.Now().Add(-time.Minute)
+		rotation.DeleteAfter = &past
+		interactor.Rotations.SaveRotation(*rotation)
+
+		rotation, _, err = interactor.ResumeKeyRotation("iasstest", "test", rotation.ID, domain.KeyRequest{})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rotation.State).Should(Equal(domain.RotationCompleted))
+		Ω(repo.Keys).Should(HaveLen(1))
+	})
+
+	It("Should complete the rotations past their grace period when asked", func() {
+		rotation, _, err := interactor.StartKeyRotation("iasstest", "test", request(&newKey))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		expired, err := interactor.ShowExpiredRotations()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(expired).Should(BeEmpty())
+
+		past := time.Now().Add(-time.Minute)
+		rotation.DeleteAfter = &past
+		interactor.Rotations.SaveRotation(*rotation)
+
+		rotations, err := interactor.ShowKeyRotations("iasstest", "test")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rotations[0].State).Should(Equal(domain.RotationCreated))
+		Ω(repo.Keys).Should(HaveLen(2))
+
+		expired, err = interactor.ShowExpiredRotations()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(expired).Should(HaveLen(1))
+		Ω(expired[0].ID).Should(Equal(rotation.ID))
+
+		completed, err := interactor.CompleteExpiredRotations("iasstest", "test")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(completed).Should(HaveLen(1))
+		Ω(completed[0].State).Should(Equal(domain.RotationCompleted))
+		Ω(repo.Keys).Should(HaveLen(1))
+		Ω(*repo.Keys[0].ID).Should(Equal(2))
+
+		expired, err = interactor.ShowExpiredRotations()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(expired).Should(BeEmpty())
+	})
+
+	It("Should start one rotation when two are requested at once", func() {
+		repo.KeysDelay = 20 * time.Millisecond
+
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				defer GinkgoRecover()
+				_, _, err := interactor.StartKeyRotation("iasstest", "test", request(&newKey))
+				errs <- err
+			}()
+		}
+
+		failed := []error{}
+		for i := 0; i < 2; i++ {
+			if err := <-errs; err != nil {
+				failed = append(failed, err)
+			}
+		}
+		Ω(failed).Should(Equal([]error{domain.ErrRotationInProgress}))
+		Ω(repo.Keys).Should(HaveLen(2))
+	})
+
+	It("Should resume a rotation interrupted after the replacement was registered", func() {
+		rotation, _, err := interactor.StartKeyRotation("iasstest", "test", request(&newKey))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		// Simulate a crash before the new key ID was saved
+		rotation.State = domain.RotationPending
+		rotation.NewKeyID = nil
+		rotation.DeleteAfter = nil
+		interactor.Rotations.SaveRotation(*rotation)
+
+		rotation, _, err = interactor.ResumeKeyRotation("iasstest", "test", rotation.ID, domain.KeyRequest{})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rotation.State).Should(Equal(domain.RotationCreated))
+		Ω(*rotation.NewKeyID).Should(Equal(2))
+		Ω(repo.Keys).Should(HaveLen(2))
+	})
+
+	It("Should replace a generated key whose private half was lost", func() {
+		rotation, _, err := interactor.StartKeyRotation("iasstest", "test", request(nil))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		rotation.State = domain.RotationPending
+		rotation.NewKeyID = nil
+		interactor.Rotations.SaveRotation(*rotation)
+
+		rotation, generated, err := interactor.ResumeKeyRotation("iasstest", "test", rotation.ID, domain.KeyRequest{})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(generated.PrivateKey).ShouldNot(BeEmpty())
+		Ω(*rotation.NewKeyID).Should(Equal(3))
+		Ω(repo.Keys).Should(HaveLen(2))
+	})
+
+	It("Should not rotate a key that does not exist", func() {
+		r := request(nil)
+		r.Title = github.String("missing")
+		_, _, err := interactor.StartKeyRotation("iasstest", "test", r)
+		Ω(err).Should(Equal(domain.ErrKeyNotFound))
+	})
+})
@@ -0,0 +1,43 @@
+package usecases
+
+import "github.com/Tinker-Ware/gh-service/domain"
+
+var deploymentStates = map[string]bool{
+	domain.DeploymentQueued:     true,
+	domain.DeploymentInProgress: true,
+	domain.DeploymentPending:    true,
+	domain.DeploymentSuccess:    true,
+	domain.DeploymentFailure:    true,
+	domain.DeploymentError:      true,
+	domain.DeploymentInactive:   true,
+}
+
+func (interactor GHInteractor) CreateDeployment(username, repo string, deployment *domain.Deployment) (*domain.Deployment, error) {
+	d, err := interactor.GithubRepository.CreateDeployment(username, repo, deployment)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (interactor GHInteractor) ShowDeployments(username, repo, environment string) ([]domain.Deployment, error) {
+	deployments, err := interactor.GithubRepository.ListDeployments(username, repo, environment)
+	if err != nil {
+		return nil, err
+	}
+	return deployments, nil
+}
+
+// UpdateDeploymentStatus reports a new state of a deployment, the state is
+// checked before calling github
+func (interactor GHInteractor) UpdateDeploymentStatus(username, repo string, id int, status *domain.DeploymentStatus) (*domain.DeploymentStatus, error) {
+	if status.State == nil || !deploymentStates[*status.State] {
+		return nil, domain.ErrInvalidDeploymentState
+	}
+
+	s, err := interactor.GithubRepository.CreateDeploymentStatus(username, repo, id, status)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
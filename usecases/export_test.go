@@ -0,0 +1,13 @@
+package usecases
+
+import "time"
+
+// SetPollTimes shortens the polling of asynchronous github operations in the
+// specs, the returned function restores the defaults
+func SetPollTimes(interval, timeout time.Duration) func() {
+	oldInterval, oldTimeout := pollInterval, pollTimeout
+	pollInterval, pollTimeout = interval, timeout
+	return func() {
+		pollInterval, pollTimeout = oldInterval, oldTimeout
+	}
+}
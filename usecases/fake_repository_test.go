@@ -0,0 +1,242 @@
+package usecases_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+)
+
+// fakeRepository is an in-memory github shared by the specs. It answers as the
+// user iasstest, a member of Tinker-Ware, and records what the usecases send
+// to github. Methods it does not implement panic on the nil GithubRepository.
+type fakeRepository struct {
+	GithubRepository
+
+	// Repos are the repositories github has, by full name
+	Repos map[string]domain.Repository
+	// Fork is the repository github says a fork goes to
+	Fork domain.Repository
+	// RepoErr is returned by GetRepo when it is set
+	RepoErr error
+	// BranchErr is returned by GetBranch when it is set
+	BranchErr error
+	// RepoPages is the number of pages ListRepos has, with one repository each
+	RepoPages int
+	// UserKeys are the SSH keys of iasstest
+	UserKeys []domain.Key
+	// Keys are the deploy keys of the repository
+	Keys      []domain.Key
+	nextKeyID int
+	// KeysDelay is how long listing the deploy keys takes
+	KeysDelay time.Duration
+	// Hooks are the webhooks of the repository, with the secrets sent to github
+	Hooks      []domain.Hook
+	nextHookID int
+	// SecretsKey is the public key secrets are encrypted with
+	SecretsKey *[32]byte
+
+	// Tags has every tag created
+	Tags []domain.Tag
+	// Transfers has the new owner of every transfer
+	Transfers []string
+	// Listed has the user of every ListRepos call
+	Listed []string
+	// Queries has every search query
+	Queries     []string
+	Permissions map[string]string
+	Topics      []string
+	Secrets     map[string]domain.EncryptedSecret
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		Repos:       map[string]domain.Repository{},
+		Permissions: map[string]string{},
+		Secrets:     map[string]domain.EncryptedSecret{},
+	}
+}
+
+func (repo *fakeRepository) GetUser(username string) (*domain.User, error) {
+	return &domain.User{Username: "iasstest"}, nil
+}
+
+func (repo *fakeRepository) ListOrganizations() ([]domain.Organization, error) {
+	return []domain.Organization{{Login: github.String("Tinker-Ware")}}, nil
+}
+
+func (repo *fakeRepository) GetRepo(username, reponame string) (*domain.Repository, error) {
+	if repo.RepoErr != nil {
+		return nil, repo.RepoErr
+	}
+	r, ok := repo.Repos[strings.ToLower(username+"/"+reponame)]
+	if !ok {
+		return nil, domain.ErrRepoNotFound
+	}
+	return &r, nil
+}
+
+func (repo *fakeRepository) TransferRepo(username, reponame, newOwner string, teamIDs []int) error {
+	repo.Transfers = append(repo.Transfers, newOwner)
+	return nil
+}
+
+func (repo *fakeRepository) ForkRepo(owner, reponame, org, name string) (*domain.Repository, error) {
+	fork := repo.Fork
+	return &fork, nil
+}
+
+func (repo *fakeRepository) CreateTag(username, reponame string, tag *domain.Tag) error {
+	repo.Tags = append(repo.Tags, *tag)
+	return nil
+}
+
+func (repo *fakeRepository) GetBranch(username, reponame, branch string) (*domain.Branch, error) {
+	if repo.BranchErr != nil {
+		return nil, repo.BranchErr
+	}
+	return &domain.Branch{Name: github.String(branch)}, nil
+}
+
+func (repo *fakeRepository) ListRepos(username string, opt domain.RepositoryListOptions) (*domain.RepositoryPage, error) {
+	repo.Listed = append(repo.Listed, username)
+
+	page := &domain.RepositoryPage{Repositories: []domain.Repository{{}}}
+	current := opt.Page
+	if current == 0 {
+		current = 1
+	}
+	if current < repo.RepoPages {
+		page.NextPage = current + 1
+	}
+	return page, nil
+}
+
+func (repo *fakeRepository) SearchRepos(query string, opt domain.SearchOptions) (*domain.RepositorySearch, error) {
+	repo.Queries = append(repo.Queries, query)
+	return &domain.RepositorySearch{}, nil
+}
+
+func (repo *fakeRepository) SearchCode(query string, opt domain.SearchOptions) (*domain.CodeSearch, error) {
+	repo.Queries = append(repo.Queries, query)
+	return &domain.CodeSearch{}, nil
+}
+
+func (repo *fakeRepository) AddCollaborator(owner, reponame, user, permission string) (*domain.Invitation, error) {
+	repo.Permissions[user] = permission
+	return nil, nil
+}
+
+func (repo *fakeRepository) AddTeamRepo(team int, owner, reponame, permission string) error {
+	repo.Permissions[owner+"/"+reponame] = permission
+	return nil
+}
+
+func (repo *fakeRepository) ReplaceTopics(owner, reponame string, topics []string) ([]string, error) {
+	repo.Topics = topics
+	return topics, nil
+}
+
+func (repo *fakeRepository) ShowKeys(username string) ([]domain.Key, error) {
+	return append([]domain.Key{}, repo.UserKeys...), nil
+}
+
+func (repo *fakeRepository) CreateKey(username string, key *domain.Key) error {
+	key.ID = github.Int(len(repo.UserKeys) + 1)
+	repo.UserKeys = append(repo.UserKeys, *key)
+	return nil
+}
+
+func (repo *fakeRepository) AddDeployKey(username, reponame string, key *domain.Key) error {
+	repo.nextKeyID++
+	key.ID = github.Int(repo.nextKeyID)
+	repo.Keys = append(repo.Keys, *key)
+	return nil
+}
+
+func (repo *fakeRepository) ListDeployKeys(username, reponame string) ([]domain.Key, error) {
+	time.Sleep(repo.KeysDelay)
+	return append([]domain.Key{}, repo.Keys...), nil
+}
+
+func (repo *fakeRepository) DeleteDeployKey(username, reponame string, id int) error {
+	keys := []domain.Key{}
+	for _, key := range repo.Keys {
+		if *key.ID != id {
+			keys = append(keys, key)
+		}
+	}
+	repo.Keys = keys
+	return nil
+}
+
+func (repo *fakeRepository) GetSecretsPublicKey(owner, reponame, environment string) (*domain.SecretsPublicKey, error) {
+	return &domain.SecretsPublicKey{KeyID: "568250167242549743", Key: base64.StdEncoding.EncodeToString(repo.SecretsKey[:])}, nil
+}
+
+func (repo *fakeRepository) PutSecret(owner, reponame, environment, name string, secret domain.EncryptedSecret) (bool, error) {
+	key := strings.TrimPrefix(environment+"/"+name, "/")
+	_, exists := repo.Secrets[key]
+	repo.Secrets[key] = secret
+	return !exists, nil
+}
+
+func (repo *fakeRepository) ListHooks(owner, reponame string) ([]domain.Hook, error) {
+	return append([]domain.Hook{}, repo.Hooks...), nil
+}
+
+func (repo *fakeRepository) CreateHook(owner, reponame string, hook *domain.Hook) (*domain.Hook, error) {
+	repo.nextHookID++
+	h := *hook
+	h.ID = github.Int(repo.nextHookID)
+	repo.Hooks = append(repo.Hooks, h)
+	return &h, nil
+}
+
+func (repo *fakeRepository) EditHook(owner, reponame string, id int, hook *domain.Hook) (*domain.Hook, error) {
+	for i := range repo.Hooks {
+		if *repo.Hooks[i].ID == id {
+			h := *hook
+			h.ID = github.Int(id)
+			repo.Hooks[i] = h
+			return &h, nil
+		}
+	}
+	return nil, errors.New("404 Not Found")
+}
+
+func (repo *fakeRepository) DeleteHook(owner, reponame string, id int) error {
+	hooks := []domain.Hook{}
+	for _, hook := range repo.Hooks {
+		if *hook.ID != id {
+			hooks = append(hooks, hook)
+		}
+	}
+	repo.Hooks = hooks
+	return nil
+}
+
+// hookSecrets is an in-memory HookSecretStore
+type hookSecrets map[int]string
+
+func (store hookSecrets) SaveHookSecret(id int, secret string) error {
+	store[id] = secret
+	return nil
+}
+
+func (store hookSecrets) GetHookSecret(id int) (string, error) {
+	secret, ok := store[id]
+	if !ok {
+		return "", domain.ErrHookSecretNotFound
+	}
+	return secret, nil
+}
+
+func (store hookSecrets) DeleteHookSecret(id int) error {
+	delete(store, id)
+	return nil
+}
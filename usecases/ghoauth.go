@@ -1,25 +1,87 @@
 package usecases
 
-import "github.com/Tinker-Ware/gh-service/domain"
+import (
+	"io"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
 
 type GHInteractor struct {
-	GithubRepository GithubRepository
+	GithubRepository    GithubRepository
+	HookSecrets         HookSecretStore
+	Rotations           RotationStore
+	RotationGracePeriod time.Duration
+	StackDetectors      []StackDetector
+	ReadinessChecks     []ReadinessCheck
 }
 
 type GithubRepository interface {
 	GetOauthURL() (string, string)
 	GetToken(code, givenState, incomingStates string) (*domain.User, error)
 	SetToken(token string)
-	GetAllRepos(username string) ([]domain.Repository, error)
+	ListRepos(username string, opt domain.RepositoryListOptions) (*domain.RepositoryPage, error)
+	SearchRepos(query string, opt domain.SearchOptions) (*domain.RepositorySearch, error)
+	SearchCode(query string, opt domain.SearchOptions) (*domain.CodeSearch, error)
 	GetRepo(username, reponame string) (*domain.Repository, error)
 	CreateRepo(username, reponame, org string, private bool) (*domain.Repository, error)
 	GetKey(username string, id int) (*domain.Key, error)
 	ShowKeys(username string) ([]domain.Key, error)
 	CreateKey(username string, key *domain.Key) error
+	DeleteKey(username string, id int) error
+	ListGPGKeys(username string) ([]domain.GPGKey, error)
+	CreateGPGKey(username, armoredPublicKey string) (*domain.GPGKey, error)
+	DeleteGPGKey(username string, id int) error
 	CreateFile(file domain.File, author domain.Author, username, repoName string) error
 	AddFiles(files []domain.File, author domain.Author, username, reponame string) error
 	GetUser(username string) (*domain.User, error)
 	AddDeployKey(username, reponame string, key *domain.Key) error
+	ListDeployKeys(username, reponame string) ([]domain.Key, error)
+	ListCollaborators(owner, reponame string) ([]domain.Collaborator, error)
+	AddCollaborator(owner, reponame, user, permission string) (*domain.Invitation, error)
+	RemoveCollaborator(owner, reponame, user string) error
+	ListInvitations(owner, reponame string) ([]domain.Invitation, error)
+	DeleteInvitation(owner, reponame string, id int) error
+	ListOrganizations() ([]domain.Organization, error)
+	ListOrgRepos(org string) ([]domain.Repository, error)
+	ListTeams(org string) ([]domain.Team, error)
+	AddTeamRepo(team int, owner, reponame, permission string) error
+	GetDeployKey(username, reponame string, id int) (*domain.Key, error)
+	DeleteDeployKey(username, reponame string, id int) error
+	SetArchived(username, reponame string, archived bool) (*domain.Repository, error)
+	GetTopics(owner, reponame string) ([]string, error)
+	ReplaceTopics(owner, reponame string, topics []string) ([]string, error)
+	GetTree(owner, reponame, ref string) ([]string, error)
+	GetFile(owner, reponame, ref, path string) ([]byte, error)
+	ListLanguages(owner, reponame string) (map[string]int, error)
+	GetSecretsPublicKey(owner, reponame, environment string) (*domain.SecretsPublicKey, error)
+	ListSecrets(owner, reponame, environment string) ([]domain.Secret, error)
+	PutSecret(owner, reponame, environment, name string, secret domain.EncryptedSecret) (bool, error)
+	DeleteSecret(owner, reponame, environment, name string) error
+	TransferRepo(username, reponame, newOwner string, teamIDs []int) error
+	ForkRepo(owner, reponame, org, name string) (*domain.Repository, error)
+	GetBranch(username, reponame, branch string) (*domain.Branch, error)
+	ListBranches(username, reponame string) ([]domain.Branch, error)
+	CreateBranch(username, reponame, branch, from string) (*domain.Branch, error)
+	DeleteBranch(username, reponame, branch string) error
+	SetDefaultBranch(username, reponame, branch string) (*domain.Repository, error)
+	GetBranchProtection(username, reponame, branch string) (*domain.BranchProtection, error)
+	UpdateBranchProtection(username, reponame, branch string, policy *domain.BranchProtection) (*domain.BranchProtection, error)
+	CreateTag(username, reponame string, tag *domain.Tag) error
+	ListReleases(username, reponame string) ([]domain.Release, error)
+	CreateRelease(username, reponame string, release *domain.Release) (*domain.Release, error)
+	UpdateRelease(username, reponame string, id int, release *domain.Release) (*domain.Release, error)
+	UploadReleaseAsset(username, reponame string, id int, name, label, contentType string, size int64, content io.Reader) (*domain.ReleaseAsset, error)
+	CreateDeployment(username, reponame string, deployment *domain.Deployment) (*domain.Deployment, error)
+	ListDeployments(username, reponame, environment string) ([]domain.Deployment, error)
+	CreateDeploymentStatus(username, reponame string, id int, status *domain.DeploymentStatus) (*domain.DeploymentStatus, error)
+	CreateStatus(username, reponame, sha string, status *domain.CommitStatus) (*domain.CommitStatus, error)
+	GetCombinedStatus(username, reponame, ref string) (*domain.CombinedStatus, error)
+	ListHooks(owner, reponame string) ([]domain.Hook, error)
+	CreateHook(owner, reponame string, hook *domain.Hook) (*domain.Hook, error)
+	EditHook(owner, reponame string, id int, hook *domain.Hook) (*domain.Hook, error)
+	DeleteHook(owner, reponame string, id int) error
+	PingHook(owner, reponame string, id int) error
 }
 
 var (
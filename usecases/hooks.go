@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// ErrNoHookSecretStore is returned when hooks are managed without a store for their secrets
+var ErrNoHookSecretStore = errors.New("webhook secrets are not stored")
+
+// defaultHookEvents are subscribed when a hook does not ask for any event
+var defaultHookEvents = []string{"push"}
+
+// HookSecretStore persists the secret of every webhook, by hook ID
+type HookSecretStore interface {
+	SaveHookSecret(id int, secret string) error
+	GetHookSecret(id int) (string, error)
+	DeleteHookSecret(id int) error
+}
+
+// Hooks are managed in the organization when repo is empty
+
+func (interactor GHInteractor) ShowHooks(owner, repo string) ([]domain.Hook, error) {
+	hooks, err := interactor.GithubRepository.ListHooks(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+// CreateHook registers a webhook with a random secret of its own, stored by the
+// ID of the hook so its deliveries can be verified. An existing hook with the
+// same URL is reused and updated instead of adding a duplicate, the returned
+// bool reports whether a new hook was created.
+func (interactor GHInteractor) CreateHook(owner, repo string, hook *domain.Hook) (*domain.Hook, bool, error) {
+	if interactor.HookSecrets == nil {
+		return nil, false, ErrNoHookSecretStore
+	}
+
+	h := *hook
+	if len(h.Events) == 0 {
+		h.Events = defaultHookEvents
+	}
+	if h.ContentType == nil {
+		contentType := "json"
+		h.ContentType = &contentType
+	}
+	if h.Active == nil {
+		active := true
+		h.Active = &active
+	}
+
+	hooks, err := interactor.GithubRepository.ListHooks(owner, repo)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, existing := range hooks {
+		if existing.ID == nil || existing.URL == nil || *existing.URL != *h.URL {
+			continue
+		}
+
+		updated, err := interactor.editHook(owner, repo, *existing.ID, &h)
+		if err != nil {
+			return nil, false, err
+		}
+		return updated, false, nil
+	}
+
+	secret, err := newHookSecret()
+	if err != nil {
+		return nil, false, err
+	}
+	h.Secret = &secret
+
+	created, err := interactor.GithubRepository.CreateHook(owner, repo, &h)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = interactor.HookSecrets.SaveHookSecret(*created.ID, secret)
+	if err != nil {
+		// Deliveries of a hook without a stored secret cannot be verified
+		deleteErr := interactor.GithubRepository.DeleteHook(owner, repo, *created.ID)
+		if deleteErr != nil {
+			log.Printf("Cannot delete hook %d without a stored secret: %s", *created.ID, deleteErr.Error())
+		}
+		return nil, false, err
+	}
+
+	created.Secret = &secret
+	return created, true, nil
+}
+
+// UpdateHook changes the events or configuration of a webhook, its secret is
+// written again when the configuration changes
+func (interactor GHInteractor) UpdateHook(owner, repo string, id int, hook *domain.Hook) (*domain.Hook, error) {
+	h := *hook
+	h.Secret = nil
+	if h.URL == nil {
+		updated, err := interactor.GithubRepository.EditHook(owner, repo, id, &h)
+		if err != nil {
+			return nil, err
+		}
+		return updated, nil
+	}
+
+	if interactor.HookSecrets == nil {
+		return nil, ErrNoHookSecretStore
+	}
+	return interactor.editHook(owner, repo, id, &h)
+}
+
+// DeleteHook removes a webhook and its stored secret
+func (interactor GHInteractor) DeleteHook(owner, repo string, id int) error {
+	err := interactor.GithubRepository.DeleteHook(owner, repo, id)
+	if err != nil {
+		return err
+	}
+
+	if interactor.HookSecrets != nil {
+		return interactor.HookSecrets.DeleteHookSecret(id)
+	}
+	return nil
+}
+
+func (interactor GHInteractor) PingHook(owner, repo string, id int) error {
+	return interactor.GithubRepository.PingHook(owner, repo, id)
+}
+
+// ShowHookSecret returns the stored secret of a hook to verify its deliveries
+func (interactor GHInteractor) ShowHookSecret(id int) (string, error) {
+	if interactor.HookSecrets == nil {
+		return "", ErrNoHookSecretStore
+	}
+	return interactor.HookSecrets.GetHookSecret(id)
+}
+
+// editHook writes the configuration of a hook with its stored secret, github
+// drops the secret when the configuration is written without it. Hooks
+// without a stored secret get a new one.
+func (interactor GHInteractor) editHook(owner, repo string, id int, hook *domain.Hook) (*domain.Hook, error) {
+	secret, err := interactor.HookSecrets.GetHookSecret(id)
+	if err == domain.ErrHookSecretNotFound {
+		secret, err = newHookSecret()
+	}
+	if err != nil {
+		return nil, err
+	}
+	hook.Secret = &secret
+
+	updated, err := interactor.GithubRepository.EditHook(owner, repo, id, hook)
+	if err != nil {
+		return nil, err
+	}
+
+	err = interactor.HookSecrets.SaveHookSecret(id, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	updated.Secret = &secret
+	return updated, nil
+}
+
+func newHookSecret() (string, error) {
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,59 @@
+package usecases_test
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manage hooks", func() {
+	var repo *fakeRepository
+	var secrets hookSecrets
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		secrets = hookSecrets{}
+		interactor = GHInteractor{GithubRepository: repo, HookSecrets: secrets}
+	})
+
+	It("Should give every hook its own stored secret", func() {
+		first, created, err := interactor.CreateHook("iasstest", "test", &domain.Hook{URL: github.String("https://ci.example/hook")})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(created).Should(BeTrue())
+		second, _, err := interactor.CreateHook("iasstest", "test", &domain.Hook{URL: github.String("https://deploy.example/hook")})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(*first.Secret).Should(HaveLen(64))
+		Ω(*first.Secret).ShouldNot(Equal(*second.Secret))
+		Ω(secrets).Should(Equal(hookSecrets{1: *first.Secret, 2: *second.Secret}))
+		Ω(*repo.Hooks[0].Secret).Should(Equal(*first.Secret))
+	})
+
+	It("Should keep the secret of a hook when it is written again", func() {
+		hook, _, err := interactor.CreateHook("iasstest", "test", &domain.Hook{URL: github.String("https://ci.example/hook")})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		again, created, err := interactor.CreateHook("iasstest", "test", &domain.Hook{URL: github.String("https://ci.example/hook"), Events: []string{"release"}})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(created).Should(BeFalse())
+		Ω(*again.Secret).Should(Equal(*hook.Secret))
+
+		_, err = interactor.UpdateHook("iasstest", "test", *hook.ID, &domain.Hook{URL: github.String("https://ci.example/new")})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(*repo.Hooks[0].Secret).Should(Equal(*hook.Secret))
+
+		Ω(interactor.DeleteHook("iasstest", "test", *hook.ID)).Should(Succeed())
+		Ω(secrets).Should(BeEmpty())
+	})
+
+	It("Should not create hooks without a store for their secrets", func() {
+		interactor.HookSecrets = nil
+		_, _, err := interactor.CreateHook("iasstest", "test", &domain.Hook{URL: github.String("https://ci.example/hook")})
+		Ω(err).Should(Equal(ErrNoHookSecretStore))
+		Ω(repo.Hooks).Should(BeEmpty())
+	})
+})
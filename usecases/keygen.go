@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/pem"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/ssh"
+)
+
+const rsaKeyBits = 4096
+
+// GenerateDeployKey creates a key pair, registers its public half as a deploy
+// key of the repository and returns the private half in the OpenSSH format.
+// The private key is not kept, this is the only time it is available.
+func (interactor GHInteractor) GenerateDeployKey(username, reponame string, request domain.KeyRequest) (*domain.GeneratedKey, error) {
+	recipient, err := validateKeyRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	private, public, err := generateKeyPair(request.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := ""
+	if request.Title != nil {
+		comment = *request.Title
+	}
+
+	var block *pem.Block
+	if request.Passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(private, comment, []byte(request.Passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(private, comment)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	authorizedKey := string(ssh.MarshalAuthorizedKey(public))
+	key := domain.Key{
+		Title:    request.Title,
+		Key:      &authorizedKey,
+		ReadOnly: request.ReadOnly,
+	}
+
+	err = interactor.GithubRepository.AddDeployKey(username, reponame, &key)
+	if err != nil {
+		return nil, err
+	}
+	setFingerprint(&key)
+
+	generated := &domain.GeneratedKey{
+		Key:        key,
+		PrivateKey: string(pem.EncodeToMemory(block)),
+		Encryption: domain.KeyEncryptionNone,
+	}
+
+	switch {
+	case request.Passphrase != "":
+		generated.Encryption = domain.KeyEncryptionPassphrase
+	case recipient != nil:
+		sealed, err := box.SealAnonymous(nil, []byte(generated.PrivateKey), recipient, rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		generated.PrivateKey = base64.StdEncoding.EncodeToString(sealed)
+		generated.Encryption = domain.KeyEncryptionRecipient
+	}
+
+	return generated, nil
+}
+
+// validateKeyRequest checks a key request before anything is registered in
+// github, it returns the recipient of the private key if there is one
+func validateKeyRequest(request domain.KeyRequest) (*[32]byte, error) {
+	switch request.Type {
+	case "", domain.KeyTypeED25519, domain.KeyTypeRSA:
+	default:
+		return nil, domain.ErrInvalidKeyType
+	}
+
+	if request.Passphrase != "" && request.Recipient != "" {
+		return nil, domain.ErrKeyEncryption
+	}
+
+	if request.Recipient == "" {
+		return nil, nil
+	}
+
+	return parseRecipient(request.Recipient)
+}
+
+func generateKeyPair(keyType string) (crypto.Signer, ssh.PublicKey, error) {
+	var private crypto.Signer
+	var err error
+
+	switch keyType {
+	case "", domain.KeyTypeED25519:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	case domain.KeyTypeRSA:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	default:
+		return nil, nil, domain.ErrInvalidKeyType
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	public, err := ssh.NewPublicKey(private.Public())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return private, public, nil
+}
+
+func parseRecipient(recipient string) (*[32]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(recipient)
+	if err != nil || len(data) != 32 {
+		return nil, domain.ErrInvalidRecipient
+	}
+
+	key := &[32]byte{}
+	copy(key[:], data)
+	return key, nil
+}
@@ -0,0 +1,90 @@
+package usecases_test
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+	"golang.org/x/crypto/nacl/box"
+	"golang.org/x/crypto/ssh"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate deploy keys", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	publicKey := func(private interface{}) string {
+		signer, err := ssh.NewSignerFromKey(private)
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+	}
+
+	It("Should register an ed25519 key and return its private half", func() {
+		generated, err := interactor.GenerateDeployKey("iasstest", "test", domain.KeyRequest{Title: github.String("provisioning")})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(generated.Encryption).Should(Equal(domain.KeyEncryptionNone))
+		Ω(*generated.Key.ID).Should(Equal(1))
+		Ω(repo.Keys).Should(HaveLen(1))
+		Ω(*repo.Keys[0].Key).Should(HavePrefix("ssh-ed25519 "))
+
+		private, err := ssh.ParseRawPrivateKey([]byte(generated.PrivateKey))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(publicKey(private)).Should(Equal(*repo.Keys[0].Key))
+	})
+
+	It("Should encrypt the private key with a passphrase", func() {
+		generated, err := interactor.GenerateDeployKey("iasstest", "test", domain.KeyRequest{Passphrase: "secret"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(generated.Encryption).Should(Equal(domain.KeyEncryptionPassphrase))
+
+		_, err = ssh.ParseRawPrivateKey([]byte(generated.PrivateKey))
+		Ω(err).Should(HaveOccurred())
+
+		private, err := ssh.ParseRawPrivateKeyWithPassphrase([]byte(generated.PrivateKey), []byte("secret"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(publicKey(private)).Should(Equal(*repo.Keys[0].Key))
+	})
+
+	It("Should seal the private key for a recipient", func() {
+		public, private, err := box.GenerateKey(rand.Reader)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		generated, err := interactor.GenerateDeployKey("iasstest", "test", domain.KeyRequest{
+			Recipient: base64.StdEncoding.EncodeToString(public[:]),
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(generated.Encryption).Should(Equal(domain.KeyEncryptionRecipient))
+
+		sealed, err := base64.StdEncoding.DecodeString(generated.PrivateKey)
+		Ω(err).ShouldNot(HaveOccurred())
+		opened, ok := box.OpenAnonymous(nil, sealed, public, private)
+		Ω(ok).Should(BeTrue())
+
+		key, err := ssh.ParseRawPrivateKey(opened)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(publicKey(key)).Should(Equal(*repo.Keys[0].Key))
+	})
+
+	It("Should reject invalid requests before registering a key", func() {
+		_, err := interactor.GenerateDeployKey("iasstest", "test", domain.KeyRequest{Type: "dsa"})
+		Ω(err).Should(Equal(domain.ErrInvalidKeyType))
+
+		_, err = interactor.GenerateDeployKey("iasstest", "test", domain.KeyRequest{Recipient: "c2hvcnQ="})
+		Ω(err).Should(Equal(domain.ErrInvalidRecipient))
+
+		_, err = interactor.GenerateDeployKey("iasstest", "test", domain.KeyRequest{Passphrase: "secret", Recipient: "c2hvcnQ="})
+		Ω(err).Should(Equal(domain.ErrKeyEncryption))
+
+		Ω(repo.Keys).Should(BeEmpty())
+	})
+})
@@ -1,21 +1,56 @@
 package usecases
 
-import "github.com/Tinker-Ware/gh-service/domain"
+import (
+	"crypto/rsa"
+	"strings"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"golang.org/x/crypto/ssh"
+)
+
+const minRSABits = 2048
+
+// The key methods manage the keys of the user that owns the token, username
+// must be that user
 
 func (interactor GHInteractor) ShowKeys(username string) ([]domain.Key, error) {
+	err := interactor.checkKeyOwner(username)
+	if err != nil {
+		return nil, err
+	}
 
 	keys, err := interactor.GithubRepository.ShowKeys(username)
 	if err != nil {
 		return nil, err
 	}
 
-	return keys, nil
+	return withFingerprints(keys), nil
 
 }
 
+// CreateKey validates the key and adds it to the user account, a key the
+// account already has is returned instead of creating a duplicate
 func (interactor GHInteractor) CreateKey(username string, key *domain.Key) error {
+	err := checkKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = interactor.checkKeyOwner(username)
+	if err != nil {
+		return err
+	}
 
-	err := interactor.GithubRepository.CreateKey(username, key)
+	keys, err := interactor.GithubRepository.ShowKeys(username)
+	if err != nil {
+		return err
+	}
+	if existing := findKey(keys, *key.Fingerprint); existing != nil {
+		*key = *existing
+		return nil
+	}
+
+	err = interactor.GithubRepository.CreateKey(username, key)
 	if err != nil {
 		return err
 	}
@@ -23,15 +58,186 @@ func (interactor GHInteractor) CreateKey(username string, key *domain.Key) error
 }
 
 func (interactor GHInteractor) ShowKey(username string, id int) (*domain.Key, error) {
+	err := interactor.checkKeyOwner(username)
+	if err != nil {
+		return nil, err
+	}
 
 	key, err := interactor.GithubRepository.GetKey(username, id)
 	if err != nil {
 		return nil, err
 	}
+	setFingerprint(key)
+	return key, nil
+}
+
+func (interactor GHInteractor) DeleteKey(username string, id int) error {
+	err := interactor.checkKeyOwner(username)
+	if err != nil {
+		return err
+	}
+	return interactor.GithubRepository.DeleteKey(username, id)
+}
+
+func (interactor GHInteractor) ShowGPGKeys(username string) ([]domain.GPGKey, error) {
+	err := interactor.checkKeyOwner(username)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := interactor.GithubRepository.ListGPGKeys(username)
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (interactor GHInteractor) CreateGPGKey(username, armoredPublicKey string) (*domain.GPGKey, error) {
+	err := interactor.checkKeyOwner(username)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := interactor.GithubRepository.CreateGPGKey(username, armoredPublicKey)
+	if err != nil {
+		return nil, err
+	}
 	return key, nil
 }
 
+func (interactor GHInteractor) DeleteGPGKey(username string, id int) error {
+	err := interactor.checkKeyOwner(username)
+	if err != nil {
+		return err
+	}
+	return interactor.GithubRepository.DeleteGPGKey(username, id)
+}
+
+// checkKeyOwner returns ErrNotKeyOwner when username is not the user that owns
+// the token
+func (interactor GHInteractor) checkKeyOwner(username string) error {
+	owner, err := interactor.GithubRepository.GetUser("")
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(owner.Username, username) {
+		return domain.ErrNotKeyOwner
+	}
+	return nil
+}
+
+// AddDeployKey validates the key and registers it in the repository, a key the
+// repository already has with the same access is returned instead of creating a
+// duplicate. Github does not allow the same key twice, so a key with another
+// access is a conflict.
 func (interactor GHInteractor) AddDeployKey(username, reponame string, key *domain.Key) error {
-	err := interactor.GithubRepository.AddDeployKey(username, reponame, key)
-	return err
+	err := checkKey(key)
+	if err != nil {
+		return err
+	}
+
+	keys, err := interactor.GithubRepository.ListDeployKeys(username, reponame)
+	if err != nil {
+		return err
+	}
+	if existing := findKey(keys, *key.Fingerprint); existing != nil {
+		if readOnly(key) != readOnly(existing) {
+			return domain.ErrKeyAccessConflict
+		}
+		*key = *existing
+		return nil
+	}
+
+	err = interactor.GithubRepository.AddDeployKey(username, reponame, key)
+	if err != nil {
+		return err
+	}
+	setFingerprint(key)
+	return nil
+}
+
+func (interactor GHInteractor) ShowDeployKeys(username, reponame string) ([]domain.Key, error) {
+	keys, err := interactor.GithubRepository.ListDeployKeys(username, reponame)
+	if err != nil {
+		return nil, err
+	}
+	return withFingerprints(keys), nil
+}
+
+func (interactor GHInteractor) ShowDeployKey(username, reponame string, id int) (*domain.Key, error) {
+	key, err := interactor.GithubRepository.GetDeployKey(username, reponame, id)
+	if err != nil {
+		return nil, err
+	}
+	setFingerprint(key)
+	return key, nil
+}
+
+func (interactor GHInteractor) DeleteDeployKey(username, reponame string, id int) error {
+	return interactor.GithubRepository.DeleteDeployKey(username, reponame, id)
+}
+
+// checkKey parses a public key in the authorized_keys format, rejects the key
+// types that are not safe to use and sets its fingerprint
+func checkKey(key *domain.Key) error {
+	if key.Key == nil {
+		return domain.ErrInvalidKey
+	}
+
+	public, _, _, _, err := ssh.ParseAuthorizedKey([]byte(*key.Key))
+	if err != nil {
+		return domain.ErrInvalidKey
+	}
+
+	switch public.Type() {
+	case ssh.KeyAlgoDSA:
+		return domain.ErrUnsupportedKey
+	case ssh.KeyAlgoRSA:
+		rsaKey, ok := public.(ssh.CryptoPublicKey).CryptoPublicKey().(*rsa.PublicKey)
+		if !ok || rsaKey.N.BitLen() < minRSABits {
+			return domain.ErrWeakKey
+		}
+	}
+
+	fingerprint := ssh.FingerprintSHA256(public)
+	key.Fingerprint = &fingerprint
+	return nil
+}
+
+// setFingerprint fills the fingerprint of a key read from github
+func setFingerprint(key *domain.Key) {
+	if key.Key == nil {
+		return
+	}
+
+	public, _, _, _, err := ssh.ParseAuthorizedKey([]byte(*key.Key))
+	if err != nil {
+		return
+	}
+
+	fingerprint := ssh.FingerprintSHA256(public)
+	key.Fingerprint = &fingerprint
+}
+
+func withFingerprints(keys []domain.Key) []domain.Key {
+	for i := range keys {
+		setFingerprint(&keys[i])
+	}
+	return keys
+}
+
+// readOnly reports whether a deploy key is read only, which it is unless
+// ReadOnly is false
+func readOnly(key *domain.Key) bool {
+	return key.ReadOnly == nil || *key.ReadOnly
+}
+
+// findKey returns the key with a fingerprint
+func findKey(keys []domain.Key, fingerprint string) *domain.Key {
+	for _, key := range withFingerprints(keys) {
+		if key.Fingerprint != nil && *key.Fingerprint == fingerprint {
+			return &key
+		}
+	}
+	return nil
 }
@@ -0,0 +1,37 @@
+package usecases_test
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manage user keys", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	It("Should only manage the keys of the user that owns the token", func() {
+		key := domain.Key{Title: github.String("laptop"), Key: github.String(readKey("ed25519_old.pub"))}
+		Ω(interactor.CreateKey("IassTest", &key)).Should(Succeed())
+
+		keys, err := interactor.ShowKeys("iasstest")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(keys).Should(HaveLen(1))
+
+		_, err = interactor.ShowKeys("octocat")
+		Ω(err).Should(Equal(domain.ErrNotKeyOwner))
+
+		other := domain.Key{Title: github.String("laptop"), Key: github.String(readKey("ed25519_new.pub"))}
+		Ω(interactor.CreateKey("octocat", &other)).Should(Equal(domain.ErrNotKeyOwner))
+		Ω(interactor.DeleteKey("octocat", 1)).Should(Equal(domain.ErrNotKeyOwner))
+		Ω(repo.UserKeys).Should(HaveLen(1))
+	})
+})
@@ -0,0 +1,40 @@
+package usecases
+
+import "github.com/Tinker-Ware/gh-service/domain"
+
+func (interactor GHInteractor) ShowOrganizations() ([]domain.Organization, error) {
+	orgs, err := interactor.GithubRepository.ListOrganizations()
+	if err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (interactor GHInteractor) ShowOrgRepos(org string) ([]domain.Repository, error) {
+	repos, err := interactor.GithubRepository.ListOrgRepos(org)
+	if err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+func (interactor GHInteractor) ShowTeams(org string) ([]domain.Team, error) {
+	teams, err := interactor.GithubRepository.ListTeams(org)
+	if err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// GrantTeamAccess gives a team a permission on a repository, push when it is
+// empty
+func (interactor GHInteractor) GrantTeamAccess(team int, owner, repo, permission string) error {
+	if permission == "" {
+		permission = domain.PermissionPush
+	}
+	if !repoPermissions[permission] {
+		return domain.ErrInvalidPermission
+	}
+
+	return interactor.GithubRepository.AddTeamRepo(team, owner, repo, permission)
+}
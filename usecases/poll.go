@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTimeout is returned when github does not finish an asynchronous operation in time
+var ErrTimeout = errors.New("timed out waiting for github to complete the operation")
+
+var (
+	pollInterval = 2 * time.Second
+	pollTimeout  = 2 * time.Minute
+)
+
+// waitFor calls check until it reports the operation as done, returns an error,
+// the poll timeout expires or ctx is done
+func waitFor(ctx context.Context, check func() (bool, error)) error {
+	deadline := time.Now().Add(pollTimeout)
+	for {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package usecases
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// ReadinessTarget is the repository the readiness checks look at. Tree is nil
+// when the ref cannot be read and TreeErr says why, DefaultBranchErr is set
+// when the default branch cannot be read.
+type ReadinessTarget struct {
+	Repository       *domain.Repository
+	Ref              string
+	Tree             *Tree
+	TreeErr          error
+	Stacks           []domain.Stack
+	DefaultBranchErr error
+}
+
+// ReadinessCheck looks at one requirement of a repository to be provisioned
+type ReadinessCheck func(target *ReadinessTarget) domain.Check
+
+// ReadinessChecks are the checks that can be configured by name
+var ReadinessChecks = map[string]ReadinessCheck{
+	"entrypoint":     CheckEntrypoint,
+	"runtime":        CheckRuntimeVersion,
+	"secrets":        CheckCommittedSecrets,
+	"default_branch": CheckDefaultBranch,
+	"admin":          CheckAdminRights,
+}
+
+// DefaultReadinessChecks are run when the interactor has no ReadinessChecks
+var DefaultReadinessChecks = []ReadinessCheck{
+	CheckEntrypoint,
+	CheckRuntimeVersion,
+	CheckCommittedSecrets,
+	CheckDefaultBranch,
+	CheckAdminRights,
+}
+
+// SelectReadinessChecks returns the checks with the names, in that order
+func SelectReadinessChecks(names []string) ([]ReadinessCheck, error) {
+	checks := []ReadinessCheck{}
+	for _, name := range names {
+		check, ok := ReadinessChecks[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown readiness check %s", name)
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, nil
+}
+
+// secretFiles are the names of files that usually hold credentials
+var secretFiles = map[string]bool{
+	".env":             true,
+	".netrc":           true,
+	".htpasswd":        true,
+	".pgpass":          true,
+	"id_rsa":           true,
+	"id_dsa":           true,
+	"id_ecdsa":         true,
+	"id_ed25519":       true,
+	"credentials.json": true,
+	"secrets.yml":      true,
+	"secrets.yaml":     true,
+}
+
+// secretExtensions are the extensions of private keys and certificate stores
+var secretExtensions = map[string]bool{
+	".pem": true,
+	".key": true,
+	".p12": true,
+	".pfx": true,
+	".jks": true,
+}
+
+// CheckReadiness runs the readiness checks on a repository at ref, or at its
+// default branch when ref is empty. A check that cannot look at the
+// repository fails, only errors reading the repository itself are returned.
+func (interactor GHInteractor) CheckReadiness(owner, repo, ref string) (*domain.ReadinessReport, error) {
+	r, err := interactor.GithubRepository.GetRepo(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &ReadinessTarget{Repository: r, Ref: ref}
+
+	defaultBranch := ""
+	if r.DefaultBranch != nil {
+		defaultBranch = *r.DefaultBranch
+	}
+	if target.Ref == "" {
+		target.Ref = defaultBranch
+	}
+
+	_, target.DefaultBranchErr = interactor.GithubRepository.GetBranch(owner, repo, defaultBranch)
+
+	target.Tree, _, target.TreeErr = interactor.repositoryTree(owner, repo, target.Ref)
+	if target.Tree != nil {
+		target.Stacks = interactor.detectStacks(target.Tree)
+	}
+
+	checks := interactor.ReadinessChecks
+	if checks == nil {
+		checks = DefaultReadinessChecks
+	}
+
+	report := &domain.ReadinessReport{
+		Ref:    target.Ref,
+		Status: domain.CheckPass,
+		Checks: []domain.Check{},
+	}
+	for _, check := range checks {
+		result := check(target)
+		report.Checks = append(report.Checks, result)
+
+		switch {
+		case result.Status == domain.CheckFail:
+			report.Status = domain.CheckFail
+		case result.Status == domain.CheckWarn && report.Status == domain.CheckPass:
+			report.Status = domain.CheckWarn
+		}
+	}
+
+	return report, nil
+}
+
+// CheckEntrypoint passes when there is a Dockerfile or a Procfile in the root
+// of the repository and warns when they are only in subdirectories
+func CheckEntrypoint(target *ReadinessTarget) domain.Check {
+	check := domain.Check{Name: "entrypoint"}
+	if target.Tree == nil {
+		return treeFailure(check, target)
+	}
+
+	found := target.Tree.Find("Dockerfile", "Procfile")
+	switch {
+	case len(found) == 0:
+		check.Status = domain.CheckFail
+		check.Message = "no Dockerfile or Procfile to build the application"
+	case strings.Contains(found[0], "/"):
+		check.Status = domain.CheckWarn
+		check.Message = fmt.Sprintf("no Dockerfile or Procfile in the root, found %s", strings.Join(found, ", "))
+	default:
+		check.Status = domain.CheckPass
+		check.Message = fmt.Sprintf("built with %s", found[0])
+	}
+
+	return check
+}
+
+// CheckRuntimeVersion passes when a detected stack declares its version
+func CheckRuntimeVersion(target *ReadinessTarget) domain.Check {
+	check := domain.Check{Name: "runtime"}
+	if target.Tree == nil {
+		return treeFailure(check, target)
+	}
+
+	declared := []string{}
+	undeclared := []string{}
+	for _, stack := range target.Stacks {
+		if stack.Name == "docker-compose" {
+			continue
+		}
+		if stack.Version != "" {
+			declared = append(declared, stack.Name+" "+stack.Version)
+		} else {
+			undeclared = append(undeclared, stack.Name)
+		}
+	}
+
+	switch {
+	case len(declared) > 0:
+		check.Status = domain.CheckPass
+		check.Message = fmt.Sprintf("runtime declared: %s", strings.Join(declared, ", "))
+	case len(undeclared) > 0:
+		check.Status = domain.CheckWarn
+		check.Message = fmt.Sprintf("no version declared for %s", strings.Join(undeclared, ", "))
+	default:
+		check.Status = domain.CheckFail
+		check.Message = "no runtime detected"
+	}
+
+	return check
+}
+
+// CheckCommittedSecrets fails when the repository has files that usually hold
+// credentials, like .env files and private keys. Example env files are allowed.
+func CheckCommittedSecrets(target *ReadinessTarget) domain.Check {
+	check := domain.Check{Name: "secrets"}
+	if target.Tree == nil {
+		return treeFailure(check, target)
+	}
+
+	found := []string{}
+	for _, p := range target.Tree.Paths {
+		if inSkippedDir(p) {
+			continue
+		}
+		if isSecretFile(p) {
+			found = append(found, p)
+		}
+	}
+
+	if len(found) > 0 {
+		check.Status = domain.CheckFail
+		check.Message = fmt.Sprintf("files that usually hold secrets are committed: %s", strings.Join(found, ", "))
+		return check
+	}
+
+	check.Status = domain.CheckPass
+	check.Message = "no secrets in well known paths"
+	return check
+}
+
+// CheckDefaultBranch fails when the default branch of the repository cannot be read
+func CheckDefaultBranch(target *ReadinessTarget) domain.Check {
+	check := domain.Check{Name: "default_branch"}
+
+	if target.Repository.DefaultBranch == nil || *target.Repository.DefaultBranch == "" {
+		check.Status = domain.CheckFail
+		check.Message = "the repository has no default branch"
+		return check
+	}
+
+	if target.DefaultBranchErr != nil {
+		check.Status = domain.CheckFail
+		check.Message = fmt.Sprintf("cannot read the default branch %s: %s", *target.Repository.DefaultBranch, target.DefaultBranchErr.Error())
+		return check
+	}
+
+	check.Status = domain.CheckPass
+	check.Message = fmt.Sprintf("the default branch %s is accessible", *target.Repository.DefaultBranch)
+	return check
+}
+
+// CheckAdminRights fails when the user is not an admin of the repository,
+// deploy keys cannot be added without admin rights
+func CheckAdminRights(target *ReadinessTarget) domain.Check {
+	check := domain.Check{Name: "admin"}
+
+	if target.Repository.Permissions == nil || !target.Repository.Permissions.Admin {
+		check.Status = domain.CheckFail
+		check.Message = "admin rights on the repository are needed to add deploy keys"
+		return check
+	}
+
+	check.Status = domain.CheckPass
+	check.Message = "the user is an admin of the repository"
+	return check
+}
+
+func treeFailure(check domain.Check, target *ReadinessTarget) domain.Check {
+	check.Status = domain.CheckFail
+	check.Message = fmt.Sprintf("cannot read %s: %s", target.Ref, target.TreeErr.Error())
+	return check
+}
+
+func isSecretFile(p string) bool {
+	base := path.Base(p)
+	if secretFiles[base] || secretExtensions[path.Ext(base)] {
+		return true
+	}
+	if strings.HasSuffix(p, ".aws/credentials") || strings.HasSuffix(p, ".docker/config.json") {
+		return true
+	}
+
+	// .env.production is a secret, .env.example documents the variables
+	if strings.HasPrefix(base, ".env.") {
+		switch strings.TrimPrefix(base, ".env.") {
+		case "example", "sample", "template", "dist":
+			return false
+		}
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,107 @@
+package usecases_test
+
+import (
+	"errors"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// readinessRepository serves a fixture tree with the permissions of the user
+// and a default branch that may not be readable
+type readinessRepository struct {
+	fixtureRepository
+	admin     bool
+	branchErr error
+}
+
+func (repo readinessRepository) GetRepo(username, reponame string) (*domain.Repository, error) {
+	return &domain.Repository{
+		DefaultBranch: github.String("master"),
+		Permissions:   &domain.RepositoryPermissions{Admin: repo.admin, Push: true, Pull: true},
+	}, nil
+}
+
+func (repo readinessRepository) GetBranch(username, reponame, branch string) (*domain.Branch, error) {
+	if repo.branchErr != nil {
+		return nil, repo.branchErr
+	}
+	return &domain.Branch{Name: github.String(branch)}, nil
+}
+
+var _ = Describe("Check readiness", func() {
+	statuses := func(report *domain.ReadinessReport) map[string]string {
+		result := map[string]string{}
+		for _, check := range report.Checks {
+			result[check.Name] = check.Status
+		}
+		return result
+	}
+
+	It("Should pass a repository ready to be provisioned", func() {
+		interactor := GHInteractor{GithubRepository: readinessRepository{
+			fixtureRepository: fixtureRepository{fixture: "goapp"},
+			admin:             true,
+		}}
+
+		report, err := interactor.CheckReadiness("iasstest", "goapp", "")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Ref).Should(Equal("master"))
+		Ω(report.Status).Should(Equal(domain.CheckPass))
+		Ω(statuses(report)).Should(Equal(map[string]string{
+			"entrypoint":     domain.CheckPass,
+			"runtime":        domain.CheckPass,
+			"secrets":        domain.CheckPass,
+			"default_branch": domain.CheckPass,
+			"admin":          domain.CheckPass,
+		}))
+	})
+
+	It("Should report what is missing", func() {
+		interactor := GHInteractor{GithubRepository: readinessRepository{
+			fixtureRepository: fixtureRepository{fixture: "leaky"},
+			branchErr:         errors.New("404 Not Found"),
+		}}
+
+		report, err := interactor.CheckReadiness("iasstest", "leaky", "")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Status).Should(Equal(domain.CheckFail))
+		Ω(statuses(report)).Should(Equal(map[string]string{
+			"entrypoint":     domain.CheckWarn,
+			"runtime":        domain.CheckWarn,
+			"secrets":        domain.CheckFail,
+			"default_branch": domain.CheckFail,
+			"admin":          domain.CheckFail,
+		}))
+
+		for _, check := range report.Checks {
+			if check.Name == "secrets" {
+				Ω(check.Message).Should(ContainSubstring(".env,"))
+				Ω(check.Message).Should(ContainSubstring("deploy/server.pem"))
+				Ω(check.Message).ShouldNot(ContainSubstring(".env.example"))
+			}
+		}
+	})
+
+	It("Should run the configured checks", func() {
+		checks, err := SelectReadinessChecks([]string{"admin", "secrets"})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		interactor := GHInteractor{
+			GithubRepository: readinessRepository{fixtureRepository: fixtureRepository{fixture: "goapp"}},
+			ReadinessChecks:  checks,
+		}
+		report, err := interactor.CheckReadiness("iasstest", "goapp", "v1.0.0")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Ref).Should(Equal("v1.0.0"))
+		Ω(report.Checks).Should(HaveLen(2))
+		Ω(report.Checks[0].Name).Should(Equal("admin"))
+
+		_, err = SelectReadinessChecks([]string{"license"})
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"io"
+	"strings"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// CreateTag creates a tag, a tag with a message or a tagger is annotated and
+// needs the message and the name and email of the tagger
+func (interactor GHInteractor) CreateTag(username, repo string, tag *domain.Tag) error {
+	if tag.Message != "" || tag.Tagger != "" || tag.Email != "" {
+		if strings.TrimSpace(tag.Message) == "" {
+			return domain.ErrEmptyTagMessage
+		}
+		if strings.TrimSpace(tag.Tagger) == "" || !strings.Contains(tag.Email, "@") {
+			return domain.ErrInvalidTagger
+		}
+	}
+
+	return interactor.GithubRepository.CreateTag(username, repo, tag)
+}
+
+func (interactor GHInteractor) ShowReleases(username, repo string) ([]domain.Release, error) {
+	releases, err := interactor.GithubRepository.ListReleases(username, repo)
+	if err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+func (interactor GHInteractor) CreateRelease(username, repo string, release *domain.Release) (*domain.Release, error) {
+	r, err := interactor.GithubRepository.CreateRelease(username, repo, release)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (interactor GHInteractor) UpdateRelease(username, repo string, id int, release *domain.Release) (*domain.Release, error) {
+	r, err := interactor.GithubRepository.UpdateRelease(username, repo, id, release)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (interactor GHInteractor) UploadReleaseAsset(username, repo string, id int, name, label, contentType string, size int64, content io.Reader) (*domain.ReleaseAsset, error) {
+	a, err := interactor.GithubRepository.UploadReleaseAsset(username, repo, id, name, label, contentType, size, content)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
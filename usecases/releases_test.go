@@ -0,0 +1,40 @@
+package usecases_test
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Create tags", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	It("Should create lightweight and annotated tags", func() {
+		err := interactor.CreateTag("iasstest", "test", &domain.Tag{Name: "v1.0.0", Target: "master"})
+		Ω(err).ShouldNot(HaveOccurred())
+
+		err = interactor.CreateTag("iasstest", "test", &domain.Tag{Name: "v1.0.1", Target: "master", Message: "Release 1.0.1", Tagger: "iasstest", Email: "iasstest@tinkerware.io"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(repo.Tags).Should(HaveLen(2))
+	})
+
+	It("Should reject annotated tags without a message or a tagger before calling github", func() {
+		err := interactor.CreateTag("iasstest", "test", &domain.Tag{Name: "v1.0.0", Target: "master", Tagger: "iasstest", Email: "iasstest@tinkerware.io"})
+		Ω(err).Should(Equal(domain.ErrEmptyTagMessage))
+
+		err = interactor.CreateTag("iasstest", "test", &domain.Tag{Name: "v1.0.0", Target: "master", Message: "Release 1.0.0", Email: "iasstest@tinkerware.io"})
+		Ω(err).Should(Equal(domain.ErrInvalidTagger))
+
+		err = interactor.CreateTag("iasstest", "test", &domain.Tag{Name: "v1.0.0", Target: "master", Message: "Release 1.0.0", Tagger: "iasstest"})
+		Ω(err).Should(Equal(domain.ErrInvalidTagger))
+		Ω(repo.Tags).Should(BeEmpty())
+	})
+})
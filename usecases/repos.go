@@ -1,14 +1,82 @@
 package usecases
 
-import "github.com/Tinker-Ware/gh-service/domain"
+import (
+	"context"
+	"strings"
 
-func (interactor GHInteractor) ShowRepos(username string) ([]domain.Repository, error) {
+	"github.com/Tinker-Ware/gh-service/domain"
+)
 
-	repos, err := interactor.GithubRepository.GetAllRepos(username)
+// ShowRepos returns a page of the repositories of username. The authenticated
+// user gets every repository it has access to, the repositories of any other
+// user are its public ones.
+func (interactor GHInteractor) ShowRepos(username string, opt domain.RepositoryListOptions) (*domain.RepositoryPage, error) {
+	owner, err := interactor.reposOwner(username, opt)
 	if err != nil {
 		return nil, err
 	}
-	return repos, nil
+
+	return interactor.GithubRepository.ListRepos(owner, opt)
+}
+
+// StreamRepos lists the same repositories as ShowRepos from opt.Page to the
+// last page, every page is passed to emit as soon as it is fetched. No more
+// pages are fetched once ctx is done or emit fails.
+func (interactor GHInteractor) StreamRepos(ctx context.Context, username string, opt domain.RepositoryListOptions, emit func([]domain.Repository) error) error {
+	owner, err := interactor.reposOwner(username, opt)
+	if err != nil {
+		return err
+	}
+
+	if opt.PerPage == 0 {
+		opt.PerPage = domain.MaxPerPage
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		page, err := interactor.GithubRepository.ListRepos(owner, opt)
+		if err != nil {
+			return err
+		}
+
+		err = emit(page.Repositories)
+		if err != nil {
+			return err
+		}
+
+		if page.NextPage == 0 {
+			return nil
+		}
+		opt.Page = page.NextPage
+	}
+}
+
+// reposOwner validates opt and returns the username to list the repositories
+// of, which is empty for the authenticated user
+func (interactor GHInteractor) reposOwner(username string, opt domain.RepositoryListOptions) (string, error) {
+	err := validateListOptions(opt)
+	if err != nil {
+		return "", err
+	}
+
+	owner, err := interactor.GithubRepository.GetUser("")
+	if err != nil {
+		return "", err
+	}
+
+	if strings.EqualFold(owner.Username, username) {
+		return "", nil
+	}
+
+	if opt.Visibility != "" || opt.Affiliation != "" ||
+		opt.Type == "public" || opt.Type == "private" {
+		return "", domain.ErrOwnReposFilter
+	}
+
+	return username, nil
 }
 
 func (interactor GHInteractor) CreateRepo(username, reponame, org string, private bool) (*domain.Repository, error) {
@@ -27,3 +95,152 @@ func (interactor GHInteractor) ShowRepo(username, repo string) (*domain.Reposito
 	}
 	return r, nil
 }
+
+func (interactor GHInteractor) ArchiveRepo(username, repo string) (*domain.Repository, error) {
+	r, err := interactor.GithubRepository.SetArchived(username, repo, true)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (interactor GHInteractor) UnarchiveRepo(username, repo string) (*domain.Repository, error) {
+	r, err := interactor.GithubRepository.SetArchived(username, repo, false)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TransferRepo transfers a repository to another user or organization and waits
+// until github reports it in its new location. It returns ErrTransferPending
+// when the transfer is accepted but does not finish while it is polled, polling
+// stops when ctx is done.
+func (interactor GHInteractor) TransferRepo(ctx context.Context, username, repo, newOwner string, teamIDs []int) (*domain.Repository, error) {
+	err := interactor.GithubRepository.TransferRepo(username, repo, newOwner, teamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var r *domain.Repository
+	err = waitFor(ctx, func() (bool, error) {
+		// The repository is not found under the new owner until the transfer finishes
+		rp, err := interactor.GithubRepository.GetRepo(newOwner, repo)
+		if err == domain.ErrRepoNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if rp.FullName == nil || !strings.EqualFold(*rp.FullName, newOwner+"/"+repo) {
+			return false, nil
+		}
+		r = rp
+		return true, nil
+	})
+	if err != nil && (err == ErrTimeout || err == ctx.Err()) {
+		// Github keeps moving the repository when the client stops waiting
+		return nil, domain.ErrTransferPending
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// ForkRepo forks a repository into the user account, or into org when given, and
+// waits until the default branch of the fork can be read so the fork is ready to
+// receive files and deploy keys. It returns the fork as github created it and
+// ErrForkPending when it is not ready while it is polled, polling stops when ctx
+// is done.
+func (interactor GHInteractor) ForkRepo(ctx context.Context, owner, repo, org, name string) (*domain.Repository, error) {
+	fork, err := interactor.GithubRepository.ForkRepo(owner, repo, org, name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Github chooses where the fork goes, it renames it when the account has a
+	// repository with the same name and returns existing forks as they are
+	if fork.FullName == nil || !strings.Contains(*fork.FullName, "/") {
+		return nil, domain.ErrInvalidFork
+	}
+	location := strings.SplitN(*fork.FullName, "/", 2)
+	forkOwner, forkName := location[0], location[1]
+
+	var r *domain.Repository
+	err = waitFor(ctx, func() (bool, error) {
+		rp, err := interactor.GithubRepository.GetRepo(forkOwner, forkName)
+		if err == domain.ErrRepoNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if rp.DefaultBranch == nil {
+			return false, nil
+		}
+		_, err = interactor.GithubRepository.GetBranch(forkOwner, forkName, *rp.DefaultBranch)
+		if err == domain.ErrBranchNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		r = rp
+		return true, nil
+	})
+	if err != nil && (err == ErrTimeout || err == ctx.Err()) {
+		return fork, domain.ErrForkPending
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func validateListOptions(opt domain.RepositoryListOptions) error {
+	switch opt.Visibility {
+	case "", "all", "public", "private":
+	default:
+		return domain.ErrInvalidVisibility
+	}
+
+	if opt.Affiliation != "" {
+		for _, a := range strings.Split(opt.Affiliation, ",") {
+			switch a {
+			case "owner", "collaborator", "organization_member":
+			default:
+				return domain.ErrInvalidAffiliation
+			}
+		}
+	}
+
+	switch opt.Type {
+	case "", "all", "owner", "public", "private", "member":
+	default:
+		return domain.ErrInvalidRepoType
+	}
+	if opt.Type != "" && (opt.Visibility != "" || opt.Affiliation != "") {
+		return domain.ErrRepoTypeConflict
+	}
+
+	switch opt.Sort {
+	case "", "created", "updated", "pushed", "full_name":
+	default:
+		return domain.ErrInvalidSort
+	}
+
+	switch opt.Direction {
+	case "", "asc", "desc":
+	default:
+		return domain.ErrInvalidDirection
+	}
+
+	if opt.Page < 0 || opt.PerPage < 0 || opt.PerPage > domain.MaxPerPage {
+		return domain.ErrInvalidPage
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("List repositories", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	It("Should list every repository of the authenticated user", func() {
+		_, err := interactor.ShowRepos("IassTest", domain.RepositoryListOptions{Visibility: "private"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(repo.Listed).Should(Equal([]string{""}))
+	})
+
+	It("Should list the public repositories of another user", func() {
+		_, err := interactor.ShowRepos("octocat", domain.RepositoryListOptions{Type: "owner", Sort: "pushed"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(repo.Listed).Should(Equal([]string{"octocat"}))
+
+		_, err = interactor.ShowRepos("octocat", domain.RepositoryListOptions{Visibility: "private"})
+		Ω(err).Should(Equal(domain.ErrOwnReposFilter))
+		Ω(repo.Listed).Should(HaveLen(1))
+	})
+
+	It("Should validate the options before calling github", func() {
+		invalid := map[error]domain.RepositoryListOptions{
+			domain.ErrInvalidVisibility:  {Visibility: "internal"},
+			domain.ErrInvalidAffiliation: {Affiliation: "owner,friend"},
+			domain.ErrRepoTypeConflict:   {Type: "owner", Visibility: "public"},
+			domain.ErrInvalidSort:        {Sort: "stars"},
+			domain.ErrInvalidDirection:   {Direction: "up"},
+			domain.ErrInvalidPage:        {PerPage: 101},
+		}
+		for expected, opt := range invalid {
+			_, err := interactor.ShowRepos("iasstest", opt)
+			Ω(err).Should(Equal(expected))
+		}
+		Ω(repo.Listed).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("Stream repositories", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		repo.RepoPages = 3
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	It("Should emit every page", func() {
+		repos := 0
+		err := interactor.StreamRepos(context.Background(), "iasstest", domain.RepositoryListOptions{}, func(page []domain.Repository) error {
+			repos += len(page)
+			return nil
+		})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(repo.Listed).Should(HaveLen(3))
+		Ω(repos).Should(Equal(3))
+	})
+
+	It("Should stop fetching when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := interactor.StreamRepos(ctx, "iasstest", domain.RepositoryListOptions{}, func(page []domain.Repository) error {
+			cancel()
+			return nil
+		})
+		Ω(err).Should(Equal(context.Canceled))
+		Ω(repo.Listed).Should(HaveLen(1))
+	})
+})
+
+var _ = Describe("Transfer repositories", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+	var restore func()
+
+	BeforeEach(func() {
+		restore = SetPollTimes(time.Millisecond, 20*time.Millisecond)
+		repo = newFakeRepository()
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	AfterEach(func() {
+		restore()
+	})
+
+	It("Should return the repository from its new owner", func() {
+		repo.Repos["tinker-ware/test"] = domain.Repository{FullName: github.String("Tinker-Ware/test")}
+
+		r, err := interactor.TransferRepo(context.Background(), "iasstest", "test", "Tinker-Ware", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(*r.FullName).Should(Equal("Tinker-Ware/test"))
+		Ω(repo.Transfers).Should(Equal([]string{"Tinker-Ware"}))
+	})
+
+	It("Should report a transfer that is still in progress", func() {
+		_, err := interactor.TransferRepo(context.Background(), "iasstest", "test", "Tinker-Ware", nil)
+		Ω(err).Should(Equal(domain.ErrTransferPending))
+	})
+
+	It("Should stop polling when the new location cannot be read", func() {
+		repo.RepoErr = errors.New("403 Must have admin rights to Repository")
+		_, err := interactor.TransferRepo(context.Background(), "iasstest", "test", "Tinker-Ware", nil)
+		Ω(err).Should(Equal(repo.RepoErr))
+	})
+
+	It("Should stop polling when the request is done", func() {
+		defer SetPollTimes(time.Millisecond, time.Minute)()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := interactor.TransferRepo(ctx, "iasstest", "test", "Tinker-Ware", nil)
+		Ω(err).Should(Equal(domain.ErrTransferPending))
+	})
+})
+
+var _ = Describe("Fork repositories", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+	var restore func()
+
+	BeforeEach(func() {
+		restore = SetPollTimes(time.Millisecond, 20*time.Millisecond)
+		repo = newFakeRepository()
+		repo.Repos["iasstest/test"] = domain.Repository{FullName: github.String("iasstest/test"), DefaultBranch: github.String("master")}
+		repo.Repos["iasstest/test-1"] = domain.Repository{FullName: github.String("iasstest/test-1"), DefaultBranch: github.String("master")}
+		repo.Fork = domain.Repository{FullName: github.String("iasstest/test-1")}
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	AfterEach(func() {
+		restore()
+	})
+
+	It("Should wait for the fork where github puts it", func() {
+		r, err := interactor.ForkRepo(context.Background(), "octocat", "test", "", "")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(*r.FullName).Should(Equal("iasstest/test-1"))
+	})
+
+	It("Should report a fork that is still being prepared", func() {
+		repo.BranchErr = domain.ErrBranchNotFound
+		r, err := interactor.ForkRepo(context.Background(), "octocat", "test", "", "")
+		Ω(err).Should(Equal(domain.ErrForkPending))
+		Ω(*r.FullName).Should(Equal("iasstest/test-1"))
+	})
+
+	It("Should stop polling when the branch cannot be read", func() {
+		repo.BranchErr = errors.New("403 Resource not accessible by integration")
+		_, err := interactor.ForkRepo(context.Background(), "octocat", "test", "", "")
+		Ω(err).Should(Equal(repo.BranchErr))
+	})
+})
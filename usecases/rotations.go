@@ -0,0 +1,425 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// defaultRotationGracePeriod is used when the interactor has no grace period
+const defaultRotationGracePeriod = 24 * time.Hour
+
+// RotationStore persists deploy key rotations
+type RotationStore interface {
+	SaveRotation(rotation domain.KeyRotation) error
+	GetRotation(id string) (*domain.KeyRotation, error)
+	ListRotations(owner, repo string) ([]domain.KeyRotation, error)
+	// ListExpiredRotations returns the rotations of every repository that still
+	// have both keys after their grace period ended at now
+	ListExpiredRotations(now time.Time) ([]domain.KeyRotation, error)
+}
+
+// rotationLocks serializes the changes to the rotations of each key in the
+// process, the interactor is copied for every request so it cannot hold them
+var rotationLocks = struct {
+	sync.Mutex
+	keys map[string]*rotationLock
+}{keys: map[string]*rotationLock{}}
+
+type rotationLock struct {
+	sync.Mutex
+	users int
+}
+
+// StartKeyRotation registers a replacement for the deploy key with the title of
+// the request, the old key is deleted when the rotation is confirmed or when it
+// is completed after the grace period. The private key is returned when the
+// service generates the replacement.
+func (interactor GHInteractor) StartKeyRotation(owner, repo string, request domain.RotationRequest) (*domain.KeyRotation, *domain.GeneratedKey, error) {
+	if interactor.Rotations == nil {
+		return nil, nil, domain.ErrNoRotationStore
+	}
+
+	if request.PublicKey == nil {
+		_, err := validateKeyRequest(request.KeyRequest)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		err := checkKey(&domain.Key{Key: request.PublicKey})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	title := *request.Title
+
+	// Without the lock two requests could both find no rotation in progress and
+	// create a replacement each
+	unlock := lockRotation(owner, repo, title)
+	defer unlock()
+
+	rotations, err := interactor.Rotations.ListRotations(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	for i, rotation := range rotations {
+		if rotation.Title != title || rotation.State == domain.RotationCompleted {
+			continue
+		}
+		if !rotationExpired(rotation, now) {
+			return &rotation, nil, domain.ErrRotationInProgress
+		}
+		// The old key of a rotation past its grace period is deleted before
+		// its replacement is rotated
+		_, err := interactor.completeRotation(&rotations[i])
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	keys, err := interactor.GithubRepository.ListDeployKeys(owner, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var old *domain.Key
+	for i, key := range keys {
+		if key.Title == nil || *key.Title != title {
+			continue
+		}
+		if old != nil {
+			return nil, nil, domain.ErrAmbiguousKey
+		}
+		old = &keys[i]
+	}
+	if old == nil {
+		return nil, nil, domain.ErrKeyNotFound
+	}
+
+	id, err := newRotationID()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rotation := &domain.KeyRotation{
+		ID:        id,
+		Owner:     owner,
+		Repo:      repo,
+		Title:     title,
+		State:     domain.RotationPending,
+		OldKeyID:  *old.ID,
+		PublicKey: request.PublicKey,
+		StartedAt: time.Now().UTC(),
+	}
+
+	// The old key ID is saved before github is changed so the rotation can be resumed
+	err = interactor.Rotations.SaveRotation(*rotation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return interactor.createReplacement(rotation, request.KeyRequest)
+}
+
+// ResumeKeyRotation continues a rotation from its saved state, a pending
+// rotation creates its replacement key with request and a rotation past its
+// grace period deletes the old key
+func (interactor GHInteractor) ResumeKeyRotation(owner, repo, id string, request domain.KeyRequest) (*domain.KeyRotation, *domain.GeneratedKey, error) {
+	rotation, unlock, err := interactor.lockedRotation(owner, repo, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	switch rotation.State {
+	case domain.RotationPending:
+		if rotation.PublicKey == nil {
+			_, err := validateKeyRequest(request)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return interactor.createReplacement(rotation, request)
+	case domain.RotationCreated:
+		if rotationExpired(*rotation, time.Now()) {
+			rotation, err = interactor.completeRotation(rotation)
+			return rotation, nil, err
+		}
+	}
+
+	return rotation, nil, nil
+}
+
+// ConfirmKeyRotation deletes the old key of a rotation without waiting for the
+// grace period
+func (interactor GHInteractor) ConfirmKeyRotation(owner, repo, id string) (*domain.KeyRotation, error) {
+	rotation, unlock, err := interactor.lockedRotation(owner, repo, id)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	switch rotation.State {
+	case domain.RotationPending:
+		return nil, domain.ErrRotationState
+	case domain.RotationCompleted:
+		return rotation, nil
+	}
+
+	return interactor.completeRotation(rotation)
+}
+
+// ShowKeyRotations returns the rotations of a repository as they are stored,
+// rotations past their grace period keep both keys until they are completed
+func (interactor GHInteractor) ShowKeyRotations(owner, repo string) ([]domain.KeyRotation, error) {
+	if interactor.Rotations == nil {
+		return nil, domain.ErrNoRotationStore
+	}
+
+	return interactor.Rotations.ListRotations(owner, repo)
+}
+
+// ShowExpiredRotations returns the rotations of every repository past their
+// grace period. The service does not keep github tokens so it cannot delete
+// their old keys by itself, they are completed with
+// CompleteExpiredRotations and a token of each repository.
+func (interactor GHInteractor) ShowExpiredRotations() ([]domain.KeyRotation, error) {
+	if interactor.Rotations == nil {
+		return nil, domain.ErrNoRotationStore
+	}
+
+	return interactor.Rotations.ListExpiredRotations(time.Now())
+}
+
+// CompleteExpiredRotations deletes the old keys of the rotations of a
+// repository past their grace period and returns the completed rotations. It
+// stops at the first rotation that cannot be completed, the ones before it
+// stay completed.
+func (interactor GHInteractor) CompleteExpiredRotations(owner, repo string) ([]domain.KeyRotation, error) {
+	if interactor.Rotations == nil {
+		return nil, domain.ErrNoRotationStore
+	}
+
+	rotations, err := interactor.Rotations.ListRotations(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	completed := []domain.KeyRotation{}
+	for _, r := range rotations {
+		if !rotationExpired(r, time.Now()) {
+			continue
+		}
+
+		rotation, unlock, err := interactor.lockedRotation(owner, repo, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		// Another request may have completed it while this one waited
+		if rotation.State == domain.RotationCreated {
+			rotation, err = interactor.completeRotation(rotation)
+		}
+		unlock()
+		if err != nil {
+			return nil, err
+		}
+		completed = append(completed, *rotation)
+	}
+
+	return completed, nil
+}
+
+// lockedRotation locks the rotations of the key of a rotation and reads the
+// rotation once no other request is changing it, the returned function
+// unlocks them
+func (interactor GHInteractor) lockedRotation(owner, repo, id string) (*domain.KeyRotation, func(), error) {
+	rotation, err := interactor.getRotation(owner, repo, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock := lockRotation(owner, repo, rotation.Title)
+	rotation, err = interactor.getRotation(owner, repo, id)
+	if err != nil {
+		unlock()
+		return nil, nil, err
+	}
+
+	return rotation, unlock, nil
+}
+
+func (interactor GHInteractor) getRotation(owner, repo, id string) (*domain.KeyRotation, error) {
+	if interactor.Rotations == nil {
+		return nil, domain.ErrNoRotationStore
+	}
+
+	rotation, err := interactor.Rotations.GetRotation(id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(rotation.Owner, owner) || !strings.EqualFold(rotation.Repo, repo) {
+		return nil, domain.ErrRotationNotFound
+	}
+
+	return rotation, nil
+}
+
+// createReplacement registers the new key of a pending rotation. A replacement
+// left by an interrupted rotation is adopted when it is the public key of the
+// request, otherwise it is deleted because its private half was lost.
+func (interactor GHInteractor) createReplacement(rotation *domain.KeyRotation, request domain.KeyRequest) (*domain.KeyRotation, *domain.GeneratedKey, error) {
+	keys, err := interactor.GithubRepository.ListDeployKeys(rotation.Owner, rotation.Repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, key := range keys {
+		if *key.ID == rotation.OldKeyID {
+			if request.ReadOnly == nil {
+				request.ReadOnly = key.ReadOnly
+			}
+			continue
+		}
+		if key.Title == nil || *key.Title != rotation.Title {
+			continue
+		}
+
+		if rotation.PublicKey != nil && key.Key != nil && sameKey(*key.Key, *rotation.PublicKey) {
+			rotation.NewKeyID = key.ID
+			continue
+		}
+
+		err := interactor.GithubRepository.DeleteDeployKey(rotation.Owner, rotation.Repo, *key.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var generated *domain.GeneratedKey
+	if rotation.NewKeyID == nil {
+		request.Title = &rotation.Title
+		if rotation.PublicKey != nil {
+			key := domain.Key{
+				Title:    request.Title,
+				Key:      rotation.PublicKey,
+				ReadOnly: request.ReadOnly,
+			}
+			err = interactor.GithubRepository.AddDeployKey(rotation.Owner, rotation.Repo, &key)
+			if err != nil {
+				return nil, nil, err
+			}
+			setFingerprint(&key)
+			rotation.NewKeyID = key.ID
+		} else {
+			generated, err = interactor.GenerateDeployKey(rotation.Owner, rotation.Repo, request)
+			if err != nil {
+				return nil, nil, err
+			}
+			rotation.NewKeyID = generated.Key.ID
+		}
+	}
+
+	gracePeriod := interactor.RotationGracePeriod
+	if gracePeriod == 0 {
+		gracePeriod = defaultRotationGracePeriod
+	}
+	deleteAfter := time.Now().UTC().Add(gracePeriod)
+
+	rotation.State = domain.RotationCreated
+	rotation.DeleteAfter = &deleteAfter
+
+	err = interactor.Rotations.SaveRotation(*rotation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rotation, generated, nil
+}
+
+// completeRotation deletes the old key unless it is already gone
+func (interactor GHInteractor) completeRotation(rotation *domain.KeyRotation) (*domain.KeyRotation, error) {
+	keys, err := interactor.GithubRepository.ListDeployKeys(rotation.Owner, rotation.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if *key.ID == rotation.OldKeyID {
+			err := interactor.GithubRepository.DeleteDeployKey(rotation.Owner, rotation.Repo, rotation.OldKeyID)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	rotation.State = domain.RotationCompleted
+	rotation.CompletedAt = &now
+
+	err = interactor.Rotations.SaveRotation(*rotation)
+	if err != nil {
+		return nil, err
+	}
+
+	return rotation, nil
+}
+
+// rotationExpired tells if a rotation still has both keys after its grace period
+func rotationExpired(rotation domain.KeyRotation, now time.Time) bool {
+	return rotation.State == domain.RotationCreated && rotation.DeleteAfter != nil && !now.Before(*rotation.DeleteAfter)
+}
+
+// lockRotation locks the rotations of the key with a title in a repository, the
+// returned function unlocks them
+func lockRotation(owner, repo, title string) func() {
+	name := strings.ToLower(owner+"/"+repo) + "/" + title
+
+	rotationLocks.Lock()
+	lock, ok := rotationLocks.keys[name]
+	if !ok {
+		lock = &rotationLock{}
+		rotationLocks.keys[name] = lock
+	}
+	lock.users++
+	rotationLocks.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+
+		rotationLocks.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(rotationLocks.keys, name)
+		}
+		rotationLocks.Unlock()
+	}
+}
+
+// sameKey compares the type and the key of two authorized_keys lines ignoring
+// their comments
+func sameKey(a, b string) bool {
+	fa := strings.Fields(a)
+	fb := strings.Fields(b)
+	if len(fa) < 2 || len(fb) < 2 {
+		return false
+	}
+
+	return fa[0] == fb[0] && fa[1] == fb[1]
+}
+
+func newRotationID() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
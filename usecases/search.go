@@ -0,0 +1,104 @@
+package usecases
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// scopeQualifier matches the qualifiers that would take a search out of the
+// repositories of the user, github reads them in any case and after any
+// character that is not part of a word, like in (org:google)
+var scopeQualifier = regexp.MustCompile(`(?i)\b(user|org|repo):`)
+
+var repoSearchSorts = map[string]bool{"": true, "stars": true, "forks": true, "updated": true}
+
+var codeSearchSorts = map[string]bool{"": true, "indexed": true}
+
+// SearchRepos searches the repositories of the user and its organizations by
+// name, topic, language or any other github qualifier
+func (interactor GHInteractor) SearchRepos(opt domain.SearchOptions) (*domain.RepositorySearch, error) {
+	if !repoSearchSorts[opt.Sort] {
+		return nil, domain.ErrInvalidSearchSort
+	}
+
+	query, err := interactor.scopedQuery(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return interactor.GithubRepository.SearchRepos(query, opt)
+}
+
+// SearchCode searches the files in the repositories of the user and its
+// organizations, a filename:Dockerfile query finds the repositories with a
+// Dockerfile
+func (interactor GHInteractor) SearchCode(opt domain.SearchOptions) (*domain.CodeSearch, error) {
+	if !codeSearchSorts[opt.Sort] {
+		return nil, domain.ErrInvalidSearchSort
+	}
+
+	query, err := interactor.scopedQuery(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	return interactor.GithubRepository.SearchCode(query, opt)
+}
+
+// scopedQuery validates a search and adds to its query a qualifier for the
+// user and for each of its organizations, or only for the owner of opt.
+// Repositories of other accounts the user collaborates on are not in the
+// scope, github search has no qualifier for them.
+func (interactor GHInteractor) scopedQuery(opt domain.SearchOptions) (string, error) {
+	query := strings.TrimSpace(opt.Query)
+	if query == "" {
+		return "", domain.ErrEmptyQuery
+	}
+	if scopeQualifier.MatchString(query) {
+		return "", domain.ErrSearchQualifier
+	}
+
+	switch opt.Order {
+	case "", "asc", "desc":
+	default:
+		return "", domain.ErrInvalidOrder
+	}
+
+	if opt.Page < 0 || opt.PerPage < 0 || opt.PerPage > domain.MaxPerPage {
+		return "", domain.ErrInvalidPage
+	}
+
+	user, err := interactor.GithubRepository.GetUser("")
+	if err != nil {
+		return "", err
+	}
+	orgs, err := interactor.GithubRepository.ListOrganizations()
+	if err != nil {
+		return "", err
+	}
+
+	qualifiers := []string{"user:" + user.Username}
+	for _, org := range orgs {
+		if org.Login != nil {
+			qualifiers = append(qualifiers, "org:"+*org.Login)
+		}
+	}
+
+	if opt.Owner != "" {
+		found := false
+		for _, q := range qualifiers {
+			if strings.EqualFold(q[strings.Index(q, ":")+1:], opt.Owner) {
+				qualifiers = []string{q}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", domain.ErrSearchScope
+		}
+	}
+
+	return query + " " + strings.Join(qualifiers, " "), nil
+}
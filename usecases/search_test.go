@@ -0,0 +1,46 @@
+package usecases_test
+
+import (
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Search", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	It("Should scope the search to the user and its organizations", func() {
+		_, err := interactor.SearchRepos(domain.SearchOptions{Query: "topic:tinkerware-managed language:go"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(repo.Queries).Should(Equal([]string{"topic:tinkerware-managed language:go user:iasstest org:Tinker-Ware"}))
+
+		_, err = interactor.SearchCode(domain.SearchOptions{Query: "filename:Dockerfile", Owner: "tinker-ware"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(repo.Queries[1]).Should(Equal("filename:Dockerfile org:Tinker-Ware"))
+	})
+
+	It("Should reject searches out of the scope", func() {
+		for _, query := range []string{"api org:google", "api (org:google)", "api ORG:google", "api -user:iasstest", "repo:google/go-github"} {
+			_, err := interactor.SearchRepos(domain.SearchOptions{Query: query})
+			Ω(err).Should(Equal(domain.ErrSearchQualifier), query)
+		}
+
+		_, err := interactor.SearchCode(domain.SearchOptions{Query: "filename:Dockerfile", Owner: "google"})
+		Ω(err).Should(Equal(domain.ErrSearchScope))
+
+		_, err = interactor.SearchRepos(domain.SearchOptions{Query: " "})
+		Ω(err).Should(Equal(domain.ErrEmptyQuery))
+
+		_, err = interactor.SearchCode(domain.SearchOptions{Query: "filename:Dockerfile", Sort: "stars"})
+		Ω(err).Should(Equal(domain.ErrInvalidSearchSort))
+		Ω(repo.Queries).Should(BeEmpty())
+	})
+})
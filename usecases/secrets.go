@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"regexp"
+	"strings"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	"golang.org/x/crypto/nacl/box"
+)
+
+var validSecretName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (interactor GHInteractor) ShowSecrets(owner, repo, environment string) ([]domain.Secret, error) {
+	secrets, err := interactor.GithubRepository.ListSecrets(owner, repo, environment)
+	if err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// SetSecret encrypts a value with the secrets public key of the repository, or
+// of the environment when it is not empty, and creates or updates the secret
+// with it. The value only leaves the service in a sealed box. It returns true
+// when the secret is created.
+func (interactor GHInteractor) SetSecret(owner, repo, environment, name string, value []byte) (bool, error) {
+	err := validateSecret(name, value)
+	if err != nil {
+		return false, err
+	}
+
+	key, err := interactor.GithubRepository.GetSecretsPublicKey(owner, repo, environment)
+	if err != nil {
+		return false, err
+	}
+
+	encrypted, err := sealSecret(key, value)
+	if err != nil {
+		return false, err
+	}
+
+	return interactor.GithubRepository.PutSecret(owner, repo, environment, name, *encrypted)
+}
+
+func (interactor GHInteractor) DeleteSecret(owner, repo, environment, name string) error {
+	if !validSecretName.MatchString(name) {
+		return domain.ErrInvalidSecretName
+	}
+	return interactor.GithubRepository.DeleteSecret(owner, repo, environment, name)
+}
+
+func validateSecret(name string, value []byte) error {
+	if !validSecretName.MatchString(name) || strings.HasPrefix(strings.ToUpper(name), "GITHUB_") {
+		return domain.ErrInvalidSecretName
+	}
+	if len(value) == 0 {
+		return domain.ErrEmptySecret
+	}
+	if len(value) > domain.MaxSecretSize {
+		return domain.ErrSecretTooLarge
+	}
+
+	return nil
+}
+
+// sealSecret encrypts a value in a libsodium sealed box, only the holder of
+// the private half of the key, github, can open it
+func sealSecret(key *domain.SecretsPublicKey, value []byte) (*domain.EncryptedSecret, error) {
+	data, err := base64.StdEncoding.DecodeString(key.Key)
+	if err != nil || len(data) != 32 {
+		return nil, domain.ErrInvalidSecretsKey
+	}
+
+	recipient := &[32]byte{}
+	copy(recipient[:], data)
+
+	sealed, err := box.SealAnonymous(nil, value, recipient, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.EncryptedSecret{
+		EncryptedValue: base64.StdEncoding.EncodeToString(sealed),
+		KeyID:          key.KeyID,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package usecases_test
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+	"golang.org/x/crypto/nacl/box"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Set secret", func() {
+	var public, private *[32]byte
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		var err error
+		public, private, err = box.GenerateKey(rand.Reader)
+		Ω(err).ShouldNot(HaveOccurred())
+		repo = newFakeRepository()
+		repo.SecretsKey = public
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	It("Should send the value in a sealed box for the repository key", func() {
+		value := []byte("s3cr3t")
+		created, err := interactor.SetSecret("iasstest", "test", "staging", "DATABASE_URL", value)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(created).Should(BeTrue())
+
+		secret := repo.Secrets["staging/DATABASE_URL"]
+		Ω(secret.KeyID).Should(Equal("568250167242549743"))
+		sealed, err := base64.StdEncoding.DecodeString(secret.EncryptedValue)
+		Ω(err).ShouldNot(HaveOccurred())
+		opened, ok := box.OpenAnonymous(nil, sealed, public, private)
+		Ω(ok).Should(BeTrue())
+		Ω(string(opened)).Should(Equal("s3cr3t"))
+
+		created, err = interactor.SetSecret("iasstest", "test", "staging", "DATABASE_URL", []byte("other"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(created).Should(BeFalse())
+	})
+
+	It("Should reject invalid secrets before calling github", func() {
+		for _, name := range []string{"", "1TOKEN", "API-KEY", "GITHUB_TOKEN", "github_token"} {
+			_, err := interactor.SetSecret("iasstest", "test", "", name, []byte("value"))
+			Ω(err).Should(Equal(domain.ErrInvalidSecretName), name)
+		}
+
+		_, err := interactor.SetSecret("iasstest", "test", "", "TOKEN", nil)
+		Ω(err).Should(Equal(domain.ErrEmptySecret))
+
+		_, err = interactor.SetSecret("iasstest", "test", "", "TOKEN", []byte(strings.Repeat("a", domain.MaxSecretSize+1)))
+		Ω(err).Should(Equal(domain.ErrSecretTooLarge))
+		Ω(repo.Secrets).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,297 @@
+package usecases
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// StackDetector returns the stack it detects in a tree, or nil when the tree
+// does not use it. Files that cannot be read only lose the version hint.
+type StackDetector func(tree *Tree) *domain.Stack
+
+// DefaultStackDetectors are used when the interactor has no StackDetectors
+var DefaultStackDetectors = []StackDetector{
+	DetectGo,
+	DetectNode,
+	DetectPython,
+	DetectRuby,
+	DetectJava,
+	DetectDocker,
+	DetectDockerCompose,
+}
+
+var (
+	goVersion          = regexp.MustCompile(`(?m)^go\s+(\S+)`)
+	rubyVersion        = regexp.MustCompile(`(?m)^\s*ruby\s+['"]([^'"]+)['"]`)
+	javaVersion        = regexp.MustCompile(`<(?:java\.version|maven\.compiler\.release|maven\.compiler\.source)>\s*([^<\s]+)\s*<`)
+	gradleVersion      = regexp.MustCompile(`(?m)sourceCompatibility\s*=\s*['"]?(?:JavaVersion\.VERSION_)?([0-9._]+)`)
+	requiresPython     = regexp.MustCompile(`(?m)^requires-python\s*=\s*['"]([^'"]+)['"]`)
+	dockerFrom         = regexp.MustCompile(`(?im)^\s*FROM\s+(?:--\S+\s+)*(\S+)`)
+	composeFileVersion = regexp.MustCompile(`(?m)^version:\s*['"]?([^'"\s]+)`)
+)
+
+// DetectStacks reports the stacks found in a repository at ref, or at its
+// default branch when ref is empty, along with the languages github counts in it
+func (interactor GHInteractor) DetectStacks(owner, repo, ref string) (*domain.StackReport, error) {
+	tree, ref, err := interactor.repositoryTree(owner, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.StackReport{
+		Ref:       ref,
+		Stacks:    interactor.detectStacks(tree),
+		Languages: languageShares(tree.Languages),
+	}
+
+	return report, nil
+}
+
+// detectStacks runs the stack detectors of the interactor on a tree
+func (interactor GHInteractor) detectStacks(tree *Tree) []domain.Stack {
+	detectors := interactor.StackDetectors
+	if detectors == nil {
+		detectors = DefaultStackDetectors
+	}
+
+	stacks := []domain.Stack{}
+	for _, detect := range detectors {
+		if stack := detect(tree); stack != nil {
+			stacks = append(stacks, *stack)
+		}
+	}
+
+	return stacks
+}
+
+// repositoryTree returns the tree of a repository at ref and the ref, which is
+// the default branch when ref is empty
+func (interactor GHInteractor) repositoryTree(owner, repo, ref string) (*Tree, string, error) {
+	if ref == "" {
+		r, err := interactor.GithubRepository.GetRepo(owner, repo)
+		if err != nil {
+			return nil, "", err
+		}
+		if r.DefaultBranch != nil {
+			ref = *r.DefaultBranch
+		}
+	}
+
+	paths, err := interactor.GithubRepository.GetTree(owner, repo, ref)
+	if err != nil {
+		return nil, "", err
+	}
+
+	languages, err := interactor.GithubRepository.ListLanguages(owner, repo)
+	if err != nil {
+		return nil, "", err
+	}
+
+	tree := NewTree(paths, languages, func(p string) ([]byte, error) {
+		return interactor.GithubRepository.GetFile(owner, repo, ref, p)
+	})
+
+	return tree, ref, nil
+}
+
+// DetectGo finds go modules, the version is the go directive of the module
+// closest to the root
+func DetectGo(tree *Tree) *domain.Stack {
+	evidence := tree.Find("go.mod")
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return &domain.Stack{
+		Name:     "go",
+		Version:  firstMatch(tree, evidence[0], goVersion),
+		Evidence: evidence,
+	}
+}
+
+// DetectNode finds node packages, the version is the node engine of the
+// package closest to the root or the one in .nvmrc
+func DetectNode(tree *Tree) *domain.Stack {
+	evidence := tree.Find("package.json")
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	stack := &domain.Stack{Name: "node", Evidence: evidence}
+
+	content, err := tree.Read(evidence[0])
+	if err == nil {
+		pkg := struct {
+			Engines struct {
+				Node string `json:"node"`
+			} `json:"engines"`
+		}{}
+		if json.Unmarshal(content, &pkg) == nil {
+			stack.Version = pkg.Engines.Node
+		}
+	}
+
+	if stack.Version == "" {
+		stack.Version = versionFile(tree, ".nvmrc")
+	}
+
+	return stack
+}
+
+// DetectPython finds python projects, the version comes from runtime.txt,
+// .python-version or the requires-python of pyproject.toml
+func DetectPython(tree *Tree) *domain.Stack {
+	evidence := tree.Find("requirements.txt", "Pipfile", "pyproject.toml", "setup.py")
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	stack := &domain.Stack{Name: "python", Evidence: evidence}
+
+	stack.Version = strings.TrimPrefix(versionFile(tree, "runtime.txt"), "python-")
+	if stack.Version == "" {
+		stack.Version = versionFile(tree, ".python-version")
+	}
+	if stack.Version == "" {
+		for _, p := range tree.Find("pyproject.toml") {
+			stack.Version = firstMatch(tree, p, requiresPython)
+			break
+		}
+	}
+
+	return stack
+}
+
+// DetectRuby finds ruby projects, the version comes from .ruby-version or the
+// ruby directive of the Gemfile
+func DetectRuby(tree *Tree) *domain.Stack {
+	evidence := tree.Find("Gemfile")
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	stack := &domain.Stack{Name: "ruby", Evidence: evidence}
+
+	stack.Version = versionFile(tree, ".ruby-version")
+	if stack.Version == "" {
+		stack.Version = firstMatch(tree, evidence[0], rubyVersion)
+	}
+
+	return stack
+}
+
+// DetectJava finds maven and gradle projects, the version is the java version
+// the project closest to the root is compiled for
+func DetectJava(tree *Tree) *domain.Stack {
+	evidence := tree.Find("pom.xml", "build.gradle", "build.gradle.kts")
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	version := javaVersion
+	if strings.HasPrefix(path.Base(evidence[0]), "build.gradle") {
+		version = gradleVersion
+	}
+
+	return &domain.Stack{
+		Name:     "java",
+		Version:  firstMatch(tree, evidence[0], version),
+		Evidence: evidence,
+	}
+}
+
+// DetectDocker finds Dockerfiles, the version is the image the last stage of
+// the Dockerfile closest to the root is built from
+func DetectDocker(tree *Tree) *domain.Stack {
+	evidence := tree.Find("Dockerfile")
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	stack := &domain.Stack{Name: "docker", Evidence: evidence}
+
+	content, err := tree.Read(evidence[0])
+	if err == nil {
+		images := dockerFrom.FindAllSubmatch(content, -1)
+		if len(images) > 0 {
+			stack.Version = string(images[len(images)-1][1])
+		}
+	}
+
+	return stack
+}
+
+// DetectDockerCompose finds compose files, the version is the format version
+// of the file closest to the root
+func DetectDockerCompose(tree *Tree) *domain.Stack {
+	evidence := tree.Find("docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml")
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	return &domain.Stack{
+		Name:     "docker-compose",
+		Version:  firstMatch(tree, evidence[0], composeFileVersion),
+		Evidence: evidence,
+	}
+}
+
+// firstMatch returns the first group of the first match of re in a file
+func firstMatch(tree *Tree, p string, re *regexp.Regexp) string {
+	content, err := tree.Read(p)
+	if err != nil {
+		return ""
+	}
+
+	match := re.FindSubmatch(content)
+	if match == nil {
+		return ""
+	}
+
+	return string(match[1])
+}
+
+// versionFile returns the content of the version file closest to the root
+// with a name, like .nvmrc or .ruby-version
+func versionFile(tree *Tree, name string) string {
+	for _, p := range tree.Find(name) {
+		content, err := tree.Read(p)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(content))
+	}
+
+	return ""
+}
+
+// languageShares sorts the languages by their bytes of code
+func languageShares(languages map[string]int) []domain.Language {
+	total := 0
+	for _, bytes := range languages {
+		total += bytes
+	}
+
+	shares := []domain.Language{}
+	for name, bytes := range languages {
+		share := domain.Language{Name: name, Bytes: bytes}
+		if total > 0 {
+			share.Percent = float64(bytes*10000/total) / 100
+		}
+		shares = append(shares, share)
+	}
+
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].Bytes != shares[j].Bytes {
+			return shares[i].Bytes > shares[j].Bytes
+		}
+		return shares[i].Name < shares[j].Name
+	})
+
+	return shares
+}
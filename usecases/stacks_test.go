@@ -0,0 +1,151 @@
+package usecases_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+	"github.com/google/go-github/github"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// fixtureTree builds a tree from a directory in testdata/stacks and counts the
+// files read from it
+func fixtureTree(name string, reads *int) *Tree {
+	root := filepath.Join("testdata", "stacks", name)
+
+	paths := []string{}
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return NewTree(paths, nil, func(p string) ([]byte, error) {
+		*reads++
+		return ioutil.ReadFile(filepath.Join(root, filepath.FromSlash(p)))
+	})
+}
+
+// fixtureRepository serves a fixture tree as the repository at any ref
+type fixtureRepository struct {
+	GithubRepository
+	fixture string
+}
+
+func (repo fixtureRepository) GetRepo(username, reponame string) (*domain.Repository, error) {
+	return &domain.Repository{DefaultBranch: github.String("master")}, nil
+}
+
+func (repo fixtureRepository) GetTree(owner, reponame, ref string) ([]string, error) {
+	reads := 0
+	return fixtureTree(repo.fixture, &reads).Paths, nil
+}
+
+func (repo fixtureRepository) GetFile(owner, reponame, ref, path string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join("testdata", "stacks", repo.fixture, filepath.FromSlash(path)))
+}
+
+func (repo fixtureRepository) ListLanguages(owner, reponame string) (map[string]int, error) {
+	return map[string]int{"Go": 3000, "Dockerfile": 1000}, nil
+}
+
+var _ = Describe("Detect stacks", func() {
+	var reads int
+
+	BeforeEach(func() {
+		reads = 0
+	})
+
+	detect := func(detector StackDetector, fixture string) *domain.Stack {
+		return detector(fixtureTree(fixture, &reads))
+	}
+
+	It("Should detect go modules and skip vendored ones", func() {
+		stack := detect(DetectGo, "goapp")
+		Ω(stack).ShouldNot(BeNil())
+		Ω(stack.Version).Should(Equal("1.21"))
+		Ω(stack.Evidence).Should(Equal([]string{"go.mod"}))
+	})
+
+	It("Should detect the image of the last stage of a Dockerfile", func() {
+		stack := detect(DetectDocker, "goapp")
+		Ω(stack.Version).Should(Equal("alpine:3.18"))
+		Ω(stack.Evidence).Should(Equal([]string{"Dockerfile"}))
+	})
+
+	It("Should detect node packages", func() {
+		stack := detect(DetectNode, "nodeapp")
+		Ω(stack.Version).Should(Equal(">=18"))
+
+		stack = detect(DetectDockerCompose, "nodeapp")
+		Ω(stack.Version).Should(Equal("3.8"))
+	})
+
+	It("Should detect python, ruby and java projects", func() {
+		Ω(detect(DetectPython, "pythonapp").Version).Should(Equal("3.11.4"))
+		Ω(detect(DetectRuby, "rubyapp").Version).Should(Equal("3.2.2"))
+		Ω(detect(DetectJava, "javaapp").Version).Should(Equal("17"))
+		Ω(detect(DetectJava, "gradleapp").Version).Should(Equal("11"))
+	})
+
+	It("Should find stacks in subdirectories", func() {
+		stack := detect(DetectNode, "monorepo")
+		Ω(stack.Evidence).Should(Equal([]string{"web/package.json"}))
+		Ω(stack.Version).Should(BeEmpty())
+
+		stack = detect(DetectPython, "monorepo")
+		Ω(stack.Version).Should(Equal(">=3.9"))
+		Ω(stack.Evidence).Should(Equal([]string{"api/pyproject.toml"}))
+	})
+
+	It("Should not detect stacks that are not there", func() {
+		for _, detector := range DefaultStackDetectors {
+			Ω(detect(detector, "docs")).Should(BeNil())
+		}
+		Ω(reads).Should(BeZero())
+	})
+
+	It("Should report the stacks of a repository with its languages", func() {
+		interactor := GHInteractor{GithubRepository: fixtureRepository{fixture: "goapp"}}
+		report, err := interactor.DetectStacks("iasstest", "goapp", "")
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(report.Ref).Should(Equal("master"))
+		Ω(report.Stacks).Should(HaveLen(2))
+		Ω(report.Stacks[0].Name).Should(Equal("go"))
+		Ω(report.Stacks[1].Name).Should(Equal("docker"))
+		Ω(report.Languages).Should(Equal([]domain.Language{
+			{Name: "Go", Bytes: 3000, Percent: 75},
+			{Name: "Dockerfile", Bytes: 1000, Percent: 25},
+		}))
+	})
+
+	It("Should use the configured detectors", func() {
+		procfile := func(tree *Tree) *domain.Stack {
+			if paths := tree.Find("Procfile"); len(paths) > 0 {
+				return &domain.Stack{Name: "procfile", Evidence: paths}
+			}
+			return nil
+		}
+
+		interactor := GHInteractor{
+			GithubRepository: fixtureRepository{fixture: "pythonapp"},
+			StackDetectors:   []StackDetector{procfile},
+		}
+		report, err := interactor.DetectStacks("iasstest", "pythonapp", "v1.0.0")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(report.Ref).Should(Equal("v1.0.0"))
+		Ω(report.Stacks).Should(Equal([]domain.Stack{{Name: "procfile", Evidence: []string{"Procfile"}}}))
+	})
+})
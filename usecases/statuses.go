@@ -0,0 +1,32 @@
+package usecases
+
+import "github.com/Tinker-Ware/gh-service/domain"
+
+var commitStates = map[string]bool{
+	domain.StatusPending: true,
+	domain.StatusSuccess: true,
+	domain.StatusFailure: true,
+	domain.StatusError:   true,
+}
+
+// CreateStatus reports the result of a check on a commit, the state is
+// checked before calling github
+func (interactor GHInteractor) CreateStatus(username, repo, sha string, status *domain.CommitStatus) (*domain.CommitStatus, error) {
+	if status.State == nil || !commitStates[*status.State] {
+		return nil, domain.ErrInvalidCommitState
+	}
+
+	s, err := interactor.GithubRepository.CreateStatus(username, repo, sha, status)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (interactor GHInteractor) ShowCombinedStatus(username, repo, ref string) (*domain.CombinedStatus, error) {
+	s, err := interactor.GithubRepository.GetCombinedStatus(username, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,5 @@
+module github.com/iasstest/goapp
+
+go 1.21
+
+require github.com/x/y v1.0.0
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"regexp"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+var validTopic = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+func (interactor GHInteractor) ShowTopics(owner, repo string) ([]string, error) {
+	topics, err := interactor.GithubRepository.GetTopics(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+	return topics, nil
+}
+
+// ReplaceTopics validates the topics and replaces with them every topic of the
+// repository, repeated topics are sent once
+func (interactor GHInteractor) ReplaceTopics(owner, repo string, topics []string) ([]string, error) {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, topic := range topics {
+		if !validTopic.MatchString(topic) {
+			return nil, domain.ErrInvalidTopic
+		}
+		if !seen[topic] {
+			seen[topic] = true
+			unique = append(unique, topic)
+		}
+	}
+
+	if len(unique) > domain.MaxTopics {
+		return nil, domain.ErrTooManyTopics
+	}
+
+	replaced, err := interactor.GithubRepository.ReplaceTopics(owner, repo, unique)
+	if err != nil {
+		return nil, err
+	}
+	return replaced, nil
+}
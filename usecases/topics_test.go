@@ -0,0 +1,42 @@
+package usecases_test
+
+import (
+	"fmt"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+	. "github.com/Tinker-Ware/gh-service/usecases"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replace topics", func() {
+	var repo *fakeRepository
+	var interactor GHInteractor
+
+	BeforeEach(func() {
+		repo = newFakeRepository()
+		interactor = GHInteractor{GithubRepository: repo}
+	})
+
+	It("Should send every valid topic once", func() {
+		replaced, err := interactor.ReplaceTopics("iasstest", "test", []string{"tinkerware-managed", "staging", "tinkerware-managed", "go1"})
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(replaced).Should(Equal([]string{"tinkerware-managed", "staging", "go1"}))
+	})
+
+	It("Should reject invalid topics before calling github", func() {
+		for _, topic := range []string{"Staging", "-staging", "stag ing", "", "a123456789a123456789a123456789a123456789a1234567890"} {
+			_, err := interactor.ReplaceTopics("iasstest", "test", []string{topic})
+			Ω(err).Should(Equal(domain.ErrInvalidTopic), topic)
+		}
+
+		many := []string{}
+		for i := 0; i <= domain.MaxTopics; i++ {
+			many = append(many, fmt.Sprintf("topic-%d", i))
+		}
+		_, err := interactor.ReplaceTopics("iasstest", "test", many)
+		Ω(err).Should(Equal(domain.ErrTooManyTopics))
+		Ω(repo.Topics).Should(BeNil())
+	})
+})
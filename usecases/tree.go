@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// skippedDirs hold dependencies, their files say nothing about the repository
+var skippedDirs = []string{"vendor", "node_modules"}
+
+// Tree is the list of files of a repository at a ref, the content of a file
+// is only fetched when it is read
+type Tree struct {
+	Paths     []string
+	Languages map[string]int
+	read      func(path string) ([]byte, error)
+	files     map[string][]byte
+}
+
+// NewTree creates a tree that reads its files with read
+func NewTree(paths []string, languages map[string]int, read func(path string) ([]byte, error)) *Tree {
+	return &Tree{
+		Paths:     paths,
+		Languages: languages,
+		read:      read,
+		files:     map[string][]byte{},
+	}
+}
+
+// Find returns the paths of the files with any of the names, the ones closer
+// to the root of the repository first. Files in vendored dependencies are
+// not returned.
+func (tree *Tree) Find(names ...string) []string {
+	found := []string{}
+	for _, p := range tree.Paths {
+		if inSkippedDir(p) {
+			continue
+		}
+		base := path.Base(p)
+		for _, name := range names {
+			if base == name {
+				found = append(found, p)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return strings.Count(found[i], "/") < strings.Count(found[j], "/")
+	})
+
+	return found
+}
+
+// Read returns the content of a file, every file is fetched once
+func (tree *Tree) Read(p string) ([]byte, error) {
+	if content, ok := tree.files[p]; ok {
+		return content, nil
+	}
+
+	content, err := tree.read(p)
+	if err != nil {
+		return nil, err
+	}
+	tree.files[p] = content
+
+	return content, nil
+}
+
+func inSkippedDir(p string) bool {
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		for _, skipped := range skippedDirs {
+			if dir == skipped {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -58,10 +58,10 @@ var _ = Describe("Usecases", func() {
 			})
 
 			It("Should retrieve a list of repositories", func() {
-				repos, err := interactor.ShowRepos(username)
+				page, err := interactor.ShowRepos(username, domain.RepositoryListOptions{})
 				Ω(err).ShouldNot(HaveOccurred())
 
-				Ω(len(repos)).Should(BeNumerically(">", 0))
+				Ω(len(page.Repositories)).Should(BeNumerically(">", 0))
 			})
 
 		})
@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Tinker-Ware/gh-service/domain"
+)
+
+// AllEvents registers an EventHandler for every event type
+const AllEvents = "*"
+
+// deliveryTTL is how long a delivery ID is remembered to discard duplicates
+var deliveryTTL = 24 * time.Hour
+
+// EventHandler processes an event received from a github webhook
+type EventHandler func(event domain.WebhookEvent) error
+
+// DeliveryStore persists the webhook deliveries received by the service
+type DeliveryStore interface {
+	SaveDelivery(delivery domain.Delivery) error
+	GetDelivery(id string) (*domain.Delivery, error)
+	ListDeliveries(from, to time.Time) ([]domain.Delivery, error)
+}
+
+// WebhookDispatcher delivers webhook events to the handlers registered for their
+// type, a delivery is only dispatched again if its handlers failed
+type WebhookDispatcher struct {
+	// Store keeps every delivery and its result when it is set
+	Store DeliveryStore
+
+	mu         sync.Mutex
+	handlers   map[string][]EventHandler
+	deliveries map[string]time.Time
+}
+
+// NewWebhookDispatcher initializes a WebhookDispatcher without handlers
+func NewWebhookDispatcher() *WebhookDispatcher {
+	return &WebhookDispatcher{
+		handlers:   map[string][]EventHandler{},
+		deliveries: map[string]time.Time{},
+	}
+}
+
+// Handle registers handler for an event type such as push or deploy_key
+func (dispatcher *WebhookDispatcher) Handle(eventType string, handler EventHandler) {
+	dispatcher.mu.Lock()
+	defer dispatcher.mu.Unlock()
+
+	dispatcher.handlers[eventType] = append(dispatcher.handlers[eventType], handler)
+}
+
+// Dispatch calls the handlers registered for the event type, it returns false
+// without calling them when the delivery was already dispatched
+func (dispatcher *WebhookDispatcher) Dispatch(event domain.WebhookEvent) (bool, error) {
+	dispatcher.mu.Lock()
+	now := time.Now()
+	for id, received := range dispatcher.deliveries {
+		if now.Sub(received) > deliveryTTL {
+			delete(dispatcher.deliveries, id)
+		}
+	}
+	if _, ok := dispatcher.deliveries[event.DeliveryID]; ok {
+		dispatcher.mu.Unlock()
+		return false, nil
+	}
+	dispatcher.deliveries[event.DeliveryID] = now
+	dispatcher.mu.Unlock()
+
+	err := dispatcher.run(event)
+	if err != nil {
+		// Forget the delivery so github can redeliver it
+		dispatcher.mu.Lock()
+		delete(dispatcher.deliveries, event.DeliveryID)
+		dispatcher.mu.Unlock()
+	}
+
+	return true, err
+}
+
+// Redispatch calls the handlers of an event again even if it was already
+// dispatched, it is used to replay stored deliveries
+func (dispatcher *WebhookDispatcher) Redispatch(event domain.WebhookEvent) error {
+	dispatcher.mu.Lock()
+	dispatcher.deliveries[event.DeliveryID] = time.Now()
+	dispatcher.mu.Unlock()
+
+	return dispatcher.run(event)
+}
+
+// run calls the handlers of the event until one fails and records the result
+// in the store. Only the error of the handlers is returned, a result that
+// cannot be stored is logged so handlers that succeeded are not run again.
+func (dispatcher *WebhookDispatcher) run(event domain.WebhookEvent) error {
+	dispatcher.mu.Lock()
+	handlers := []EventHandler{}
+	handlers = append(handlers, dispatcher.handlers[event.Type]...)
+	handlers = append(handlers, dispatcher.handlers[AllEvents]...)
+	dispatcher.mu.Unlock()
+
+	var err error
+	for _, handler := range handlers {
+		err = handler(event)
+		if err != nil {
+			break
+		}
+	}
+
+	storeErr := dispatcher.recordResult(event.DeliveryID, err)
+	if storeErr != nil {
+		log.Printf("Cannot store the result of delivery %s: %s", event.DeliveryID, storeErr.Error())
+	}
+
+	return err
+}
+
+// Record stores a delivery the first time it is received, github redeliveries
+// of a failed delivery keep the original record
+func (dispatcher *WebhookDispatcher) Record(delivery domain.Delivery) error {
+	if dispatcher.Store == nil {
+		return nil
+	}
+
+	_, err := dispatcher.Store.GetDelivery(delivery.ID)
+	if err == nil {
+		return nil
+	}
+	if err != domain.ErrDeliveryNotFound {
+		return err
+	}
+
+	return dispatcher.Store.SaveDelivery(delivery)
+}
+
+func (dispatcher *WebhookDispatcher) recordResult(deliveryID string, err error) error {
+	if dispatcher.Store == nil {
+		return nil
+	}
+
+	delivery, getErr := dispatcher.Store.GetDelivery(deliveryID)
+	if getErr == domain.ErrDeliveryNotFound {
+		return nil
+	}
+	if getErr != nil {
+		return getErr
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.ProcessedAt = &now
+	delivery.Result = domain.DeliveryProcessed
+	delivery.Error = ""
+	if err != nil {
+		delivery.Result = domain.DeliveryFailed
+		delivery.Error = err.Error()
+	}
+
+	return dispatcher.Store.SaveDelivery(*delivery)
+}
+
+// ShowDeliveries returns the stored deliveries received between from and to,
+// a zero time leaves that end of the range open
+func (dispatcher *WebhookDispatcher) ShowDeliveries(from, to time.Time) ([]domain.Delivery, error) {
+	if dispatcher.Store == nil {
+		return nil, domain.ErrNoDeliveryStore
+	}
+
+	return dispatcher.Store.ListDeliveries(from, to)
+}
+
+func (dispatcher *WebhookDispatcher) ShowDelivery(id string) (*domain.Delivery, error) {
+	if dispatcher.Store == nil {
+		return nil, domain.ErrNoDeliveryStore
+	}
+
+	return dispatcher.Store.GetDelivery(id)
+}